@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -135,6 +136,7 @@ func addEndPoint(isUpdateOpenAPI bool) error {
 				"NullUUID",
 				"NullString",
 				"NullText",
+				"NullEncryptedString",
 				"NullJSON",
 				"NullBool",
 				"NullInt64",
@@ -146,12 +148,23 @@ func addEndPoint(isUpdateOpenAPI bool) error {
 			Default: "NullString",
 		}, &fieldType)
 
-		newFields = append(newFields, map[string]string{"name": fieldName, "type": fieldType})
+		isBlindIndex := false
+		if fieldType == "NullEncryptedString" {
+			input(&survey.Confirm{
+				Message: "Filter by equality (add blind index)?",
+			}, &isBlindIndex)
+		}
+
+		newFields = append(newFields, map[string]string{"name": fieldName, "type": fieldType, "blind_index": strconv.FormatBool(isBlindIndex)})
 		fmt.Println()
 	}
 	newFieldStr := ""
 	for _, nf := range newFields {
 		temp := `StructFieldName app.field_type !json:"field_name" db:"m.field_name" gorm:"column:field_name"!` + "\n"
+		if nf["blind_index"] == "true" {
+			temp = `StructFieldName app.field_type !json:"field_name" db:"m.field_name" gorm:"column:field_name" blindindex:"field_name_bidx"!` + "\n" +
+				`StructFieldNameBIdx app.NullString !json:"-" db:"-" gorm:"column:field_name_bidx;index"!` + "\n"
+		}
 		temp = strings.ReplaceAll(temp, "StructFieldName", grest.String{}.PascalCase(nf["name"]))
 		temp = strings.ReplaceAll(temp, "field_type", nf["type"])
 		temp = strings.ReplaceAll(temp, "field_name", nf["name"])
@@ -206,7 +219,7 @@ func addEndPoint(isUpdateOpenAPI bool) error {
 	baseModulePath := strings.Split(string(goModContent), "\n")[0]
 	baseModulePath = strings.Replace(baseModulePath, "module ", "", 1)
 
-	for _, fileName := range []string{"src/migrator.go", "src/router.go"} {
+	for _, fileName := range []string{"src/migrator.go", "src/router.go", "src/worker.go", "src/scheduler.go"} {
 		fmt.Println("updating file :", fileName)
 		file, err := os.Open(fileName)
 		if err != nil {
@@ -224,14 +237,31 @@ func addEndPoint(isUpdateOpenAPI bool) error {
 		newRegisterTableSection := `app.DB().RegisterTable("main", ` + packagePath + "." + modelStructName + "{})\n" + registerTableSection
 		newContent = strings.Replace(newContent, registerTableSection, newRegisterTableSection, 1)
 
+		registerJobSection := "// RegisterJob : DONT REMOVE THIS COMMENT"
+		newRegisterJobSection := `app.RegisterJob(` + packagePath + "." + modelStructName + "{}.EndPoint()+\".hook\", " + packagePath + ".HookJob)\n" +
+			`app.RegisterJob(` + packagePath + "." + modelStructName + "{}.EndPoint()+\".import\", " + packagePath + ".ImportJob)\n" + registerJobSection
+		newContent = strings.Replace(newContent, registerJobSection, newRegisterJobSection, 1)
+
+		addScheduleSection := "// AddSchedule : DONT REMOVE THIS COMMENT"
+		newAddScheduleSection := `s.add(` + packagePath + "." + modelStructName + "{}.EndPoint()+\".purge_trash\", \"0 2 * * *\", " + packagePath + ".PurgeTrashTask)\n" + addScheduleSection
+		newContent = strings.Replace(newContent, addScheduleSection, newAddScheduleSection, 1)
+
 		addRouteSection := "// AddRoute : DONT REMOVE THIS COMMENT"
 		newAddRouteSection := `
 			app.Server().AddRoute("/codegentemplate", "POST", codegentemplate.REST().Create, codegentemplate.OpenAPI().Create())
 			app.Server().AddRoute("/codegentemplate", "GET", codegentemplate.REST().Get, codegentemplate.OpenAPI().Get())
+			app.Server().AddRoute("/codegentemplate/trash", "GET", codegentemplate.REST().GetTrash, codegentemplate.OpenAPI().GetTrash())
+			app.Server().AddRoute("/codegentemplate/trash", "DELETE", codegentemplate.REST().PurgeTrash, codegentemplate.OpenAPI().PurgeTrash())
+			app.Server().AddRoute("/codegentemplate/bulk", "POST", codegentemplate.REST().BulkCreate, codegentemplate.OpenAPI().BulkCreate())
+			app.Server().AddRoute("/codegentemplate/bulk", "PATCH", codegentemplate.REST().BulkPartiallyUpdate, codegentemplate.OpenAPI().BulkPartiallyUpdate())
+			app.Server().AddRoute("/codegentemplate/bulk", "DELETE", codegentemplate.REST().BulkDelete, codegentemplate.OpenAPI().BulkDelete())
+			app.Server().AddRoute("/codegentemplate/import", "POST", codegentemplate.REST().Import, codegentemplate.OpenAPI().Import())
 			app.Server().AddRoute("/codegentemplate/{id}", "GET", codegentemplate.REST().GetByID, codegentemplate.OpenAPI().GetByID())
 			app.Server().AddRoute("/codegentemplate/{id}", "PUT", codegentemplate.REST().UpdateByID, codegentemplate.OpenAPI().UpdateByID())
 			app.Server().AddRoute("/codegentemplate/{id}", "PATCH", codegentemplate.REST().PartiallyUpdateByID, codegentemplate.OpenAPI().PartiallyUpdateByID())
 			app.Server().AddRoute("/codegentemplate/{id}", "DELETE", codegentemplate.REST().DeleteByID, codegentemplate.OpenAPI().DeleteByID())
+			app.Server().AddRoute("/codegentemplate/{id}/history", "GET", codegentemplate.REST().GetHistory, codegentemplate.OpenAPI().GetHistory())
+			app.Server().AddRoute("/codegentemplate/{id}/restore", "POST", codegentemplate.REST().RestoreByID, codegentemplate.OpenAPI().RestoreByID())
 
 			// AddRoute : DONT REMOVE THIS COMMENT`
 		newAddRouteSection = strings.ReplaceAll(newAddRouteSection, "/codegentemplate", endPointPath)
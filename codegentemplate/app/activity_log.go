@@ -0,0 +1,162 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ActivityLog is the audit trail of the data changes, it is saved by Ctx.Hook after the db transaction is committed.
+type ActivityLog struct {
+	Model
+	ID        NullUUID     `json:"id"         db:"m.id"         gorm:"column:id;primaryKey"`
+	Entity    NullString   `json:"entity"     db:"m.entity"     gorm:"column:entity;index:idx_activity_logs_entity_record_id"`
+	RecordID  NullString   `json:"record_id"  db:"m.record_id"  gorm:"column:record_id;index:idx_activity_logs_entity_record_id"`
+	Method    NullString   `json:"method"     db:"m.method"     gorm:"column:method"`
+	Reason    NullText     `json:"reason"     db:"m.reason"     gorm:"column:reason"`
+	UserID    NullString   `json:"user_id"    db:"m.user_id"    gorm:"column:user_id"`
+	Changes   NullJSON     `json:"changes"    db:"m.changes"    gorm:"column:changes"`
+	CreatedAt NullDateTime `json:"created_at" db:"m.created_at" gorm:"column:created_at"`
+}
+
+// EndPoint returns the ActivityLog end point, it used for cache key, etc.
+func (ActivityLog) EndPoint() string {
+	return "activity_logs"
+}
+
+// TableVersion returns the versions of the ActivityLog table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (ActivityLog) TableVersion() string {
+	return "2026-10-19_10.00"
+}
+
+// TableName returns the name of the ActivityLog table in the database.
+func (ActivityLog) TableName() string {
+	return "activity_logs"
+}
+
+// TableAliasName returns the table alias name of the ActivityLog table, used for querying.
+func (ActivityLog) TableAliasName() string {
+	return "m"
+}
+
+// GetRelations returns the relations of the ActivityLog data in the database, used for querying.
+func (m *ActivityLog) GetRelations() map[string]map[string]any {
+	return m.Relations
+}
+
+// GetFilters returns the filter of the ActivityLog data in the database, used for querying.
+func (m *ActivityLog) GetFilters() []map[string]any {
+	return m.Filters
+}
+
+// GetSorts returns the default sort of the ActivityLog data in the database, used for querying.
+func (m *ActivityLog) GetSorts() []map[string]any {
+	m.AddSort(map[string]any{"column": "m.created_at", "direction": "desc"})
+	return m.Sorts
+}
+
+// GetFields returns list of the field of the ActivityLog data in the database, used for querying.
+func (m *ActivityLog) GetFields() map[string]map[string]any {
+	m.SetFields(m)
+	return m.Fields
+}
+
+// GetSchema returns the ActivityLog schema, used for querying.
+func (m *ActivityLog) GetSchema() map[string]any {
+	return m.SetSchema(m)
+}
+
+// OpenAPISchemaName returns the name of the ActivityLog schema in the open api documentation.
+func (ActivityLog) OpenAPISchemaName() string {
+	return "ActivityLog"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ActivityLog in the open api documentation.
+func (m *ActivityLog) GetOpenAPISchema() map[string]any {
+	return m.SetOpenAPISchema(m)
+}
+
+type ActivityLogList struct {
+	ListModel
+	Data []ActivityLog `json:"results"`
+}
+
+// OpenAPISchemaName returns the name of the ActivityLogList schema in the open api documentation.
+func (ActivityLogList) OpenAPISchemaName() string {
+	return "ActivityLogList"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ActivityLogList in the open api documentation.
+func (p *ActivityLogList) GetOpenAPISchema() map[string]any {
+	return p.SetOpenAPISchema(&ActivityLog{})
+}
+
+// Save saves the activity log of the entity record with the field-level diff between oldJSON and newJSON,
+// using the db transaction of the ctx.
+func (ActivityLog) Save(c Ctx, entity, method, reason, id string, oldJSON, newJSON []byte) error {
+	oldData, newData := map[string]any{}, map[string]any{}
+	json.Unmarshal(oldJSON, &oldData)
+	json.Unmarshal(newJSON, &newData)
+	changes, _ := json.Marshal(ActivityLog{}.Diff(oldData, newData))
+
+	a := ActivityLog{}
+	a.ID = NewNullUUID()
+	a.Entity = NewNullString(entity)
+	a.RecordID = NewNullString(id)
+	a.Method = NewNullString(method)
+	a.Reason = NewNullText(reason)
+	if c.UserID != "" {
+		a.UserID = NewNullString(c.UserID)
+	}
+	json.Unmarshal(changes, &a.Changes)
+	a.CreatedAt = NewNullDateTime(time.Now().UTC())
+
+	tx, err := c.DB()
+	if err == nil {
+		err = tx.Create(&a).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save activity log: %w", err)
+	}
+	return nil
+}
+
+// Diff returns the field-level diff between old and new data, for example :
+//
+//	{"name": {"old": "Kilogram", "new": "KG"}, "category.id": {"old": null, "new": "..."}}
+//
+// The nested object field name is joined with dot notation, the array field is compared as a whole.
+func (ActivityLog) Diff(old, new map[string]any) map[string]any {
+	res := map[string]any{}
+	diffJSON("", old, new, res)
+	return res
+}
+
+// diffJSON adds the changed field of old and new data to res with prefix as the field name prefix.
+func diffJSON(prefix string, old, new map[string]any, res map[string]any) {
+	keys := map[string]bool{}
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	for k := range keys {
+		o, n := old[k], new[k]
+		om, isOldObject := o.(map[string]any)
+		nm, isNewObject := n.(map[string]any)
+		if isOldObject || isNewObject {
+			if om == nil {
+				om = map[string]any{}
+			}
+			if nm == nil {
+				nm = map[string]any{}
+			}
+			diffJSON(prefix+k+".", om, nm, res)
+		} else if !reflect.DeepEqual(o, n) {
+			res[prefix+k] = map[string]any{"old": o, "new": n}
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Alerter returns a pointer to the alerterUtil instance (alrt).
+// If alrt is not initialized, it creates a new alerterUtil instance, configures it, and assigns it to alrt.
+// It ensures that only one instance of alerterUtil is created and reused.
+func Alerter() *alerterUtil {
+	if alrt == nil {
+		alrt = &alerterUtil{}
+		alrt.configure()
+	}
+	return alrt
+}
+
+// alrt is a pointer to a alerterUtil instance.
+// It is used to store and access the singleton instance of alerterUtil.
+var alrt *alerterUtil
+
+// AlertNotifier is the channel to send the alert, for example telegram, slack, webhook or email.
+// Register the custom notifier with Alerter().AddNotifier.
+type AlertNotifier interface {
+	Name() string
+	Notify(ctx context.Context, a Alert) error
+}
+
+// Alert is the alert which is sent to the notifiers when the log level is at least the notifier level.
+type Alert struct {
+	Level     string      `json:"level"`
+	Message   string      `json:"message"`
+	Path      string      `json:"path,omitempty"`
+	ErrorType string      `json:"error_type,omitempty"`
+	Attrs     []AlertAttr `json:"attrs"`
+	Detail    string      `json:"detail,omitempty"`
+	Trace     string      `json:"trace,omitempty"`
+	Repeated  int         `json:"repeated,omitempty"` // the number of the duplicates within ALERT_THROTTLE_WINDOW, the alert is the summary if it is not 0
+	Time      time.Time   `json:"time"`
+
+	isThreshold bool // true for the alert of ALERT_ERROR_RATE_THRESHOLD and ALERT_P95_LATENCY_THRESHOLD, see Fingerprint
+}
+
+// AlertAttr is the log attribute which is included on the alert, for example env, version, method and url.
+type AlertAttr struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Text returns the alert as a markdown code block.
+func (a Alert) Text() string {
+	writeln := func(s *strings.Builder, args ...string) {
+		for _, arg := range args {
+			s.WriteString(arg)
+		}
+		s.WriteByte('\n')
+	}
+
+	b := &strings.Builder{}
+	writeln(b, "```")
+	writeln(b, a.Message)
+	if a.Repeated > 0 {
+		writeln(b, "repeated: ", strconv.Itoa(a.Repeated), " times in the last ", ALERT_THROTTLE_WINDOW.String())
+	}
+	if a.Path != "" {
+		writeln(b, "path: ", a.Path)
+	}
+	for _, attr := range a.Attrs {
+		writeln(b, attr.Key, ": ", attr.Value)
+	}
+	if a.Detail != "" && a.Detail != "null" {
+		writeln(b, "error: ", a.Detail)
+	}
+	if a.Trace != "" && a.Trace != "null" {
+		writeln(b, "trace: ", a.Trace)
+	}
+	writeln(b, "```")
+	return b.String()
+}
+
+// alerterUtil sends the alert to the notifiers on the background, so the slow notifier doesn't block the log.
+type alerterUtil struct {
+	targets []alertTarget
+	queue   chan Alert
+	done    chan struct{}
+	mu      sync.RWMutex
+	pending sync.WaitGroup // the queued alerts which are not sent yet
+
+	dropped     int64 // the alerts dropped because the queue is full since the last report, see reportFailures
+	failed      int64 // the alerts failed to send since the last report, see reportFailures
+	failureMu   sync.Mutex
+	lastFailure string // the notifier name and the error of the last failed alert
+
+	throttleMu sync.Mutex
+	throttles  map[string]*alertThrottle // the throttled alerts by fingerprint
+	windowMu   sync.Mutex
+	window     alertWindow // the request statistics for the threshold alerts
+	isOff      bool        // true if APP_ENV is not one of ALERT_ENVS
+}
+
+// alertTarget is the notifier with the minimum log level to be alerted.
+type alertTarget struct {
+	notifier AlertNotifier
+	level    slog.Level
+}
+
+// configure configures the alerter utility instance with the notifiers of ALERT_NOTIFIERS :
+//
+//   - telegram : TELEGRAM_ALERT_TOKEN and TELEGRAM_ALERT_USER_ID.
+//   - slack : SLACK_ALERT_WEBHOOK_URL, the slack compatible incoming webhook (also mattermost, rocket.chat, etc).
+//   - webhook : WEBHOOK_ALERT_URL, the generic json webhook signed with WEBHOOK_ALERT_SECRET.
+//   - email : EMAIL_ALERT_SMTP_HOST, EMAIL_ALERT_FROM and EMAIL_ALERT_TO.
+//
+// Each notifier is alerted on ALERT_LEVEL or its own level (for example SLACK_ALERT_LEVEL), and only on ALERT_ENVS (empty for every env).
+func (a *alerterUtil) configure() {
+	if ALERT_ENVS != "" {
+		a.isOff = true
+		for _, env := range strings.Split(ALERT_ENVS, ",") {
+			if strings.TrimSpace(env) == APP_ENV {
+				a.isOff = false
+			}
+		}
+	}
+	level := func(l string) slog.Level {
+		if l == "" {
+			l = ALERT_LEVEL
+		}
+		return logLevel(l)
+	}
+	for _, name := range strings.Split(ALERT_NOTIFIERS, ",") {
+		switch strings.TrimSpace(name) {
+		case "telegram":
+			if TELEGRAM_ALERT_TOKEN != "" {
+				a.AddNotifier(NewTelegramNotifier(TELEGRAM_ALERT_API_URL, TELEGRAM_ALERT_TOKEN, TELEGRAM_ALERT_USER_ID), level(TELEGRAM_ALERT_LEVEL))
+			}
+		case "slack":
+			if SLACK_ALERT_WEBHOOK_URL != "" {
+				a.AddNotifier(NewSlackNotifier(SLACK_ALERT_WEBHOOK_URL), level(SLACK_ALERT_LEVEL))
+			}
+		case "webhook":
+			if WEBHOOK_ALERT_URL != "" {
+				a.AddNotifier(NewWebhookNotifier(WEBHOOK_ALERT_URL, WEBHOOK_ALERT_SECRET), level(WEBHOOK_ALERT_LEVEL))
+			}
+		case "email":
+			if EMAIL_ALERT_SMTP_HOST != "" && EMAIL_ALERT_TO != "" {
+				n := NewEmailNotifier(EMAIL_ALERT_SMTP_HOST, EMAIL_ALERT_SMTP_PORT, EMAIL_ALERT_USERNAME, EMAIL_ALERT_PASSWORD, EMAIL_ALERT_FROM, strings.Split(EMAIL_ALERT_TO, ","))
+				a.AddNotifier(n, level(EMAIL_ALERT_LEVEL))
+			}
+		}
+	}
+}
+
+// AddNotifier adds the notifier which is alerted on the log level or above.
+func (a *alerterUtil) AddNotifier(n AlertNotifier, level slog.Level) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.targets = append(a.targets, alertTarget{notifier: n, level: level})
+	a.start()
+}
+
+// IsEnabled returns true if any notifier is alerted on the log level.
+func (a *alerterUtil) IsEnabled(level slog.Level) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.isOff {
+		return false
+	}
+	for _, t := range a.targets {
+		if level >= t.level {
+			return true
+		}
+	}
+	return false
+}
+
+// Send sends the alert to the notifiers of the alert level on the background.
+// The alert is dropped when 100 alerts are waiting to be sent.
+// The duplicate alert is throttled, see throttle.
+//
+// Send is called by the log worker, so it must never log, the dropped alert is only counted and reported by the alert worker.
+func (a *alerterUtil) Send(alert Alert) {
+	if !a.throttle(alert, time.Now()) {
+		return
+	}
+	a.mu.RLock()
+	if a.queue == nil {
+		a.mu.RUnlock()
+		a.notify(alert) // not started because there is no notifier, or closed
+		return
+	}
+	a.pending.Add(1)
+	select {
+	case a.queue <- alert:
+		a.mu.RUnlock()
+		return
+	default:
+		a.pending.Done()
+	}
+	a.mu.RUnlock()
+	atomic.AddInt64(&a.dropped, 1)
+}
+
+// start starts the alert worker once, the caller must hold a.mu.
+func (a *alerterUtil) start() {
+	if a.done != nil {
+		return
+	}
+	a.queue = make(chan Alert, 100)
+	a.done = make(chan struct{})
+	go a.run(a.queue)
+}
+
+// run sends the queued alerts until the queue is closed, and every second it sends the summary of the throttled alerts and the threshold alerts.
+// The summary of every throttled alert is sent when the queue is closed.
+func (a *alerterUtil) run(queue chan Alert) {
+	defer close(a.done)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case alert, ok := <-queue:
+			if !ok {
+				for _, s := range a.dueSummaries(time.Now().Add(ALERT_THROTTLE_WINDOW)) {
+					a.notify(s)
+				}
+				a.reportFailures()
+				return
+			}
+			a.notify(alert)
+			a.pending.Done()
+		case now := <-ticker.C:
+			a.tick(now)
+			a.reportFailures()
+		}
+	}
+}
+
+// Flush waits until the queued alerts are sent, it is called before the app exits.
+func (a *alerterUtil) Flush() {
+	a.pending.Wait()
+}
+
+// notify sends the alert to every notifier of the alert level, the failure is counted and reported by reportFailures.
+func (a *alerterUtil) notify(alert Alert) {
+	level := logLevel(alert.Level)
+	a.mu.RLock()
+	targets := a.targets
+	a.mu.RUnlock()
+	for _, t := range targets {
+		if level < t.level {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ALERT_TIMEOUT)
+		err := t.notifier.Notify(ctx, alert)
+		cancel()
+		if err != nil {
+			atomic.AddInt64(&a.failed, 1)
+			a.failureMu.Lock()
+			a.lastFailure = t.notifier.Name() + ": " + err.Error()
+			a.failureMu.Unlock()
+		}
+	}
+}
+
+// reportFailures logs the dropped and failed alerts since the last report without alert.
+// It is only called by the alert worker, never by the log worker, so the log queue is never re-entered by its own worker.
+func (a *alerterUtil) reportFailures() {
+	if dropped := atomic.SwapInt64(&a.dropped, 0); dropped > 0 {
+		Logger().LogWithoutAlert(slog.LevelWarn, "Failed to send the alert because the alert queue is full", slog.Int64("dropped", dropped))
+	}
+	if failed := atomic.SwapInt64(&a.failed, 0); failed > 0 {
+		a.failureMu.Lock()
+		lastFailure := a.lastFailure
+		a.failureMu.Unlock()
+		Logger().LogWithoutAlert(slog.LevelWarn, "Failed to send the alert", slog.Int64("failed", failed), slog.String("last_error", lastFailure))
+	}
+}
+
+// Close waits until the queued alerts are sent, it is called on graceful shutdown by Logger().Close.
+func (a *alerterUtil) Close() {
+	a.mu.Lock()
+	queue, done := a.queue, a.done
+	a.queue = nil
+	a.mu.Unlock()
+	if queue != nil {
+		close(queue)
+		<-done
+	}
+}
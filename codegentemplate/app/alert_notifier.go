@@ -0,0 +1,169 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewTelegramNotifier returns the notifier which sends the alert to the telegram chat with the bot api,
+// apiURL is https://api.telegram.org or the local stand-in for testing.
+func NewTelegramNotifier(apiURL, token, chatID string) AlertNotifier {
+	return &telegramNotifier{APIURL: strings.TrimSuffix(apiURL, "/"), Token: token, ChatID: chatID}
+}
+
+type telegramNotifier struct {
+	APIURL string
+	Token  string
+	ChatID string
+}
+
+func (n *telegramNotifier) Name() string {
+	return "telegram"
+}
+
+// Notify sends the alert to the chat, the bot token is redacted from the url of the error since it is logged.
+func (n *telegramNotifier) Notify(ctx context.Context, a Alert) error {
+	body := map[string]any{"chat_id": n.ChatID, "text": a.Text(), "parse_mode": "Markdown"}
+	err := postAlert(ctx, n.APIURL+"/bot"+n.Token+"/sendMessage", body, nil)
+	var uerr *url.Error
+	if errors.As(err, &uerr) && n.Token != "" {
+		uerr.URL = strings.ReplaceAll(uerr.URL, n.Token, Redacted)
+	}
+	return err
+}
+
+// NewSlackNotifier returns the notifier which sends the alert to the slack compatible incoming webhook url.
+func NewSlackNotifier(webhookURL string) AlertNotifier {
+	return &slackNotifier{WebhookURL: webhookURL}
+}
+
+type slackNotifier struct {
+	WebhookURL string
+}
+
+func (n *slackNotifier) Name() string {
+	return "slack"
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, a Alert) error {
+	return postAlert(ctx, n.WebhookURL, map[string]any{"text": a.Text()}, nil)
+}
+
+// NewWebhookNotifier returns the notifier which posts the alert as json to the url.
+// If secret is provided, the payload is signed the same way as the webhook subscription (X-Webhook-Signature header).
+func NewWebhookNotifier(url, secret string) AlertNotifier {
+	return &webhookNotifier{URL: url, Secret: secret}
+}
+
+type webhookNotifier struct {
+	URL    string
+	Secret string
+}
+
+func (n *webhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, a Alert) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("X-Webhook-Event", "alert")
+	if n.Secret != "" {
+		timestamp := time.Now().Unix()
+		header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+		header.Set("X-Webhook-Signature", "sha256="+Webhook().Sign(n.Secret, timestamp, payload))
+	}
+	return postAlert(ctx, n.URL, json.RawMessage(payload), header)
+}
+
+// postAlert posts the json body to the url, it returns error if the response status is not 2xx.
+func postAlert(ctx context.Context, url string, body any, header http.Header) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "My App API Alert")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("unexpected response status %d: %s", res.StatusCode, string(b))
+	}
+	return nil
+}
+
+// NewEmailNotifier returns the notifier which sends the alert by email with the smtp server.
+// The smtp auth is skipped if username is empty.
+func NewEmailNotifier(host string, port int, username, password, from string, to []string) AlertNotifier {
+	n := &emailNotifier{Host: host, Port: port, Username: username, Password: password, From: from}
+	for _, t := range to {
+		if t = strings.TrimSpace(t); t != "" {
+			n.To = append(n.To, t)
+		}
+	}
+	return n
+}
+
+type emailNotifier struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+func (n *emailNotifier) Name() string {
+	return "email"
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, a Alert) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		auth = smtp.PlainAuth("", n.Username, n.Password, n.Host)
+	}
+	subject := "[" + APP_ENV + "] [" + strings.ToUpper(a.Level) + "] " + strings.SplitN(a.Message, "\n", 2)[0]
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(subject))
+	fmt.Fprintf(msg, "Date: %s\r\n", a.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(a.Text(), "\r\n", "\n"), "\n", "\r\n"))
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(net.JoinHostPort(n.Host, strconv.Itoa(n.Port)), auth, n.From, n.To, msg.Bytes())
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
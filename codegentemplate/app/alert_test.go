@@ -0,0 +1,243 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertNotifiers(t *testing.T) {
+	received := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received[r.URL.Path] = string(b)
+		if r.URL.Path == "/webhook" {
+			ts, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+			if r.Header.Get("X-Webhook-Signature") != "sha256="+Webhook().Sign("s3cret", ts, b) {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	a := Alert{Level: "error", Message: "Failed to connect to main DB", Attrs: []AlertAttr{{Key: "env", Value: "local"}}, Time: time.Now()}
+	notifiers := map[string]AlertNotifier{
+		"/botTOKEN/sendMessage": NewTelegramNotifier(srv.URL, "TOKEN", "123"),
+		"/slack":                NewSlackNotifier(srv.URL + "/slack"),
+		"/webhook":              NewWebhookNotifier(srv.URL+"/webhook", "s3cret"),
+	}
+	for path, n := range notifiers {
+		if err := n.Notify(context.Background(), a); err != nil {
+			t.Errorf("Expected %s is sent, got error [%v]", n.Name(), err)
+		}
+		if !strings.Contains(received[path], "Failed to connect to main DB") {
+			t.Errorf("Expected %s receives the alert, got [%v]", n.Name(), received[path])
+		}
+	}
+	res := Alert{}
+	json.Unmarshal([]byte(received["/webhook"]), &res)
+	if res.Level != "error" || len(res.Attrs) != 1 {
+		t.Errorf("Expected the webhook receives the json alert, got [%+v]", res)
+	}
+}
+
+func TestAlertTelegramNotifierError(t *testing.T) {
+	n := NewTelegramNotifier("http://127.0.0.1:1", "123456:SECRET-TOKEN", "123")
+	err := n.Notify(context.Background(), Alert{Level: "error", Message: "Failed to connect to main DB", Time: time.Now()})
+	if err == nil {
+		t.Fatal("Expected error for unreachable api url")
+	}
+	if strings.Contains(err.Error(), "SECRET-TOKEN") || !strings.Contains(err.Error(), "/bot"+Redacted+"/sendMessage") {
+		t.Errorf("Expected the bot token is redacted from the error, got [%v]", err)
+	}
+}
+
+func TestAlertEmailNotifier(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost")
+		body := &strings.Builder{}
+		isData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if isData {
+				if line == ".\r\n" {
+					isData = false
+					data <- body.String()
+					reply("250 OK")
+				} else {
+					body.WriteString(line)
+				}
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				isData = true
+				reply("354 Start mail input")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	n := NewEmailNotifier("127.0.0.1", addr.Port, "", "", "alert@example.com", []string{"ops@example.com"})
+	a := Alert{Level: "error", Message: "Failed to connect to main DB", Time: time.Now()}
+	if err := n.Notify(context.Background(), a); err != nil {
+		t.Fatalf("Expected the email is sent, got error [%v]", err)
+	}
+	select {
+	case msg := <-data:
+		if !strings.Contains(msg, "Subject: ["+APP_ENV+"] [ERROR] Failed to connect to main DB") || !strings.Contains(msg, "To: ops@example.com") {
+			t.Errorf("Expected the email of the alert, got [%v]", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the email is received")
+	}
+}
+
+func TestAlerterLevel(t *testing.T) {
+	a := &alerterUtil{}
+	n := NewSlackNotifier("http://127.0.0.1:0")
+	a.AddNotifier(n, slog.LevelWarn)
+	if a.IsEnabled(slog.LevelInfo) || !a.IsEnabled(slog.LevelWarn) || !a.IsEnabled(slog.LevelError) {
+		t.Error("Expected the alert is enabled on warning and above")
+	}
+	a.isOff = true
+	if a.IsEnabled(slog.LevelError) {
+		t.Error("Expected the alert is disabled when APP_ENV is not one of ALERT_ENVS")
+	}
+}
+
+func TestAlertThrottle(t *testing.T) {
+	a := &alerterUtil{}
+	now := time.Now()
+	alert := Alert{Level: "error", Message: "Failed to connect to redis", Path: "/api/end_points", ErrorType: "*net.OpError"}
+	other := alert
+	other.Path = "/api/webhooks"
+	if !a.throttle(alert, now) || !a.throttle(other, now) {
+		t.Error("Expected the first alert of each fingerprint is sent")
+	}
+	for i := 0; i < 3; i++ {
+		if a.throttle(alert, now.Add(time.Second)) {
+			t.Error("Expected the duplicate alert is throttled")
+		}
+	}
+	if s := a.dueSummaries(now.Add(time.Second)); len(s) != 0 {
+		t.Errorf("Expected no summary before the window is ended, got [%v]", s)
+	}
+	s := a.dueSummaries(now.Add(ALERT_THROTTLE_WINDOW))
+	if len(s) != 1 || s[0].Repeated != 3 || s[0].Path != alert.Path {
+		t.Errorf("Expected the summary of 3 repeated alerts, got [%+v]", s)
+	}
+	if !strings.Contains(s[0].Text(), "repeated: 3 times") {
+		t.Errorf("Expected the summary text contains the repeated count, got [%v]", s[0].Text())
+	}
+	if _, ok := a.throttles[other.Fingerprint()]; ok {
+		t.Error("Expected the fingerprint without duplicate is removed at the end of the window")
+	}
+	if a.throttle(alert, now.Add(ALERT_THROTTLE_WINDOW+time.Second)) {
+		t.Error("Expected the ongoing duplicate is throttled on the next window")
+	}
+}
+
+func TestAlertThreshold(t *testing.T) {
+	rate, latency, minRequests := ALERT_ERROR_RATE_THRESHOLD, ALERT_P95_LATENCY_THRESHOLD, ALERT_THRESHOLD_MIN_REQUESTS
+	ALERT_ERROR_RATE_THRESHOLD, ALERT_P95_LATENCY_THRESHOLD, ALERT_THRESHOLD_MIN_REQUESTS = 0.1, time.Second, 10
+	defer func() {
+		ALERT_ERROR_RATE_THRESHOLD, ALERT_P95_LATENCY_THRESHOLD, ALERT_THRESHOLD_MIN_REQUESTS = rate, latency, minRequests
+	}()
+
+	a := &alerterUtil{}
+	for i := 0; i < 20; i++ {
+		status, d := http.StatusOK, 100*time.Millisecond
+		if i < 3 {
+			status = http.StatusInternalServerError
+		}
+		if i >= 18 {
+			d = 3 * time.Second
+		}
+		a.ObserveRequest(status, d)
+	}
+	if alerts := a.thresholdAlerts(time.Now()); len(alerts) != 0 {
+		t.Errorf("Expected no alert before the window is ended, got [%v]", alerts)
+	}
+	alerts := a.thresholdAlerts(time.Now().Add(ALERT_THRESHOLD_WINDOW))
+	if len(alerts) != 2 || alerts[0].ErrorType != "error_rate" || alerts[1].ErrorType != "p95_latency" {
+		t.Fatalf("Expected the error rate and p95 latency alerts, got [%+v]", alerts)
+	}
+	if !strings.Contains(alerts[0].Message, "15.00% (3 of 20 requests)") {
+		t.Errorf("Expected the error rate on the message, got [%v]", alerts[0].Message)
+	}
+	if alerts := a.thresholdAlerts(time.Now().Add(2 * ALERT_THRESHOLD_WINDOW)); len(alerts) != 0 {
+		t.Errorf("Expected no alert on the new window without requests, got [%v]", alerts)
+	}
+}
+
+// alertTestNotifier records the sent alerts.
+type alertTestNotifier struct{ alerts []Alert }
+
+func (n *alertTestNotifier) Name() string { return "test" }
+
+func (n *alertTestNotifier) Notify(ctx context.Context, a Alert) error {
+	n.alerts = append(n.alerts, a)
+	return nil
+}
+
+func TestAlertThresholdTick(t *testing.T) {
+	rate, minRequests := ALERT_ERROR_RATE_THRESHOLD, ALERT_THRESHOLD_MIN_REQUESTS
+	ALERT_ERROR_RATE_THRESHOLD, ALERT_THRESHOLD_MIN_REQUESTS = 0.1, 1
+	defer func() { ALERT_ERROR_RATE_THRESHOLD, ALERT_THRESHOLD_MIN_REQUESTS = rate, minRequests }()
+
+	n := &alertTestNotifier{}
+	a := &alerterUtil{targets: []alertTarget{{notifier: n, level: slog.LevelError}}}
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		a.ObserveRequest(http.StatusInternalServerError, time.Millisecond)
+		for j := 0; j < i; j++ {
+			a.ObserveRequest(http.StatusOK, time.Millisecond) // the error rate is changed on every window
+		}
+		now = now.Add(ALERT_THRESHOLD_WINDOW)
+		a.tick(now)
+	}
+	if len(n.alerts) != 1 || n.alerts[0].ErrorType != "error_rate" {
+		t.Errorf("Expected the repeated threshold alert is throttled, got [%+v]", n.alerts)
+	}
+
+	n.alerts = nil
+	a = &alerterUtil{targets: []alertTarget{{notifier: n, level: slog.LevelError}}, isOff: true}
+	a.ObserveRequest(http.StatusInternalServerError, time.Millisecond)
+	a.tick(time.Now().Add(ALERT_THRESHOLD_WINDOW))
+	if len(n.alerts) != 0 {
+		t.Errorf("Expected no threshold alert when APP_ENV is not one of ALERT_ENVS, got [%+v]", n.alerts)
+	}
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+)
+
+// alertThrottle is the duplicate alerts of the same fingerprint within ALERT_THROTTLE_WINDOW.
+type alertThrottle struct {
+	alert     Alert     // the first alert of the window
+	count     int       // the suppressed duplicates
+	windowEnd time.Time // the summary is sent at the end of the window
+}
+
+// alertWindow is the request statistics of the current ALERT_THRESHOLD_WINDOW.
+type alertWindow struct {
+	start     time.Time
+	total     int64
+	errors    int64
+	latencies []time.Duration // the reservoir sample of the request latencies
+}
+
+// maxLatencySamples is the size of the reservoir sample of the request latencies to compute the p95 latency.
+const maxLatencySamples = 10000
+
+// Fingerprint returns the fingerprint of the alert by the message, the path and the error type,
+// the alerts with the same fingerprint are considered duplicates.
+// The threshold alert is fingerprinted by the error type only, since its message contains the measured value.
+func (a Alert) Fingerprint() string {
+	key := a.Level + "\n" + a.Message + "\n" + a.Path + "\n" + a.ErrorType
+	if a.isThreshold {
+		key = a.Level + "\n" + a.ErrorType
+	}
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:8])
+}
+
+// throttle returns true if the alert should be sent now. The first alert of the fingerprint is sent immediately,
+// the duplicates within ALERT_THROTTLE_WINDOW are counted and sent as a "repeated N times" summary at the end of the window.
+func (a *alerterUtil) throttle(alert Alert, now time.Time) bool {
+	if ALERT_THROTTLE_WINDOW <= 0 {
+		return true
+	}
+	a.throttleMu.Lock()
+	defer a.throttleMu.Unlock()
+	if a.throttles == nil {
+		a.throttles = map[string]*alertThrottle{}
+	}
+	fp := alert.Fingerprint()
+	if t, ok := a.throttles[fp]; ok {
+		t.count++
+		return false
+	}
+	a.throttles[fp] = &alertThrottle{alert: alert, windowEnd: now.Add(ALERT_THROTTLE_WINDOW)}
+	return true
+}
+
+// dueSummaries returns the summary of the throttled alerts which window is ended.
+// The fingerprint with duplicates starts a new window, so the ongoing failure is summarized once per window.
+func (a *alerterUtil) dueSummaries(now time.Time) []Alert {
+	a.throttleMu.Lock()
+	defer a.throttleMu.Unlock()
+	summaries := []Alert{}
+	for fp, t := range a.throttles {
+		if now.Before(t.windowEnd) {
+			continue
+		}
+		if t.count == 0 {
+			delete(a.throttles, fp)
+			continue
+		}
+		s := t.alert
+		s.Repeated = t.count
+		s.Time = now
+		summaries = append(summaries, s)
+		t.count, t.windowEnd = 0, now.Add(ALERT_THROTTLE_WINDOW)
+	}
+	return summaries
+}
+
+// ObserveRequest records the response status and the latency of the request for ALERT_ERROR_RATE_THRESHOLD and ALERT_P95_LATENCY_THRESHOLD.
+func (a *alerterUtil) ObserveRequest(status int, latency time.Duration) {
+	if ALERT_ERROR_RATE_THRESHOLD <= 0 && ALERT_P95_LATENCY_THRESHOLD <= 0 {
+		return
+	}
+	a.windowMu.Lock()
+	defer a.windowMu.Unlock()
+	w := &a.window
+	if w.start.IsZero() {
+		w.start = time.Now()
+	}
+	w.total++
+	if status >= 500 {
+		w.errors++
+	}
+	if len(w.latencies) < maxLatencySamples {
+		w.latencies = append(w.latencies, latency)
+	} else if i := rand.Int63n(w.total); i < maxLatencySamples {
+		w.latencies[i] = latency
+	}
+}
+
+// thresholdAlerts returns the alerts of the error rate and the p95 latency when the window is ended and the threshold is exceeded,
+// then starts a new window. The window with less than ALERT_THRESHOLD_MIN_REQUESTS requests is not evaluated.
+func (a *alerterUtil) thresholdAlerts(now time.Time) []Alert {
+	a.windowMu.Lock()
+	w := a.window
+	if w.start.IsZero() || now.Sub(w.start) < ALERT_THRESHOLD_WINDOW {
+		a.windowMu.Unlock()
+		return nil
+	}
+	a.window = alertWindow{start: now}
+	a.windowMu.Unlock()
+
+	alerts := []Alert{}
+	if w.total == 0 || w.total < int64(ALERT_THRESHOLD_MIN_REQUESTS) {
+		return alerts
+	}
+	hostname, _ := os.Hostname()
+	newAlert := func(errType, msg string) Alert {
+		return Alert{
+			Level:     "error",
+			Message:   msg,
+			ErrorType: errType,
+			Attrs: []AlertAttr{
+				{Key: "hostname", Value: hostname},
+				{Key: "env", Value: APP_ENV},
+				{Key: "version", Value: APP_VERSION},
+				{Key: "requests", Value: fmt.Sprint(w.total)},
+			},
+			Time:        now,
+			isThreshold: true,
+		}
+	}
+	if rate := float64(w.errors) / float64(w.total); ALERT_ERROR_RATE_THRESHOLD > 0 && rate > ALERT_ERROR_RATE_THRESHOLD {
+		alerts = append(alerts, newAlert("error_rate", fmt.Sprintf("Error rate %.2f%% (%d of %d requests) is above the threshold %.2f%% in the last %s",
+			rate*100, w.errors, w.total, ALERT_ERROR_RATE_THRESHOLD*100, ALERT_THRESHOLD_WINDOW)))
+	}
+	if p95 := percentile(w.latencies, 0.95); ALERT_P95_LATENCY_THRESHOLD > 0 && p95 > ALERT_P95_LATENCY_THRESHOLD {
+		alerts = append(alerts, newAlert("p95_latency", fmt.Sprintf("P95 latency %s is above the threshold %s in the last %s",
+			p95.Round(time.Millisecond), ALERT_P95_LATENCY_THRESHOLD, ALERT_THRESHOLD_WINDOW)))
+	}
+	return alerts
+}
+
+// percentile returns the p percentile (0 to 1) of the durations.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	i := int(float64(len(sorted))*p+0.5) - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+// tick sends the due summaries of the throttled alerts and the threshold alerts, it is called every second by the alert worker.
+// The threshold alert is always logged, but it is only sent on ALERT_ENVS and throttled the same way as the log alert.
+func (a *alerterUtil) tick(now time.Time) {
+	for _, alert := range a.dueSummaries(now) {
+		a.notify(alert)
+	}
+	for _, alert := range a.thresholdAlerts(now) {
+		Logger().LogWithoutAlert(slog.LevelError, alert.Message, slog.String("alert", alert.ErrorType))
+		if a.IsEnabled(logLevel(alert.Level)) && a.throttle(alert, now) {
+			a.notify(alert)
+		}
+	}
+}
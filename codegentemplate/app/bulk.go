@@ -0,0 +1,195 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"grest.dev/grest"
+)
+
+// These are the mode of the bulk request.
+const (
+	BulkModeAllOrNothing = "all_or_nothing" // rollback all items if any item is failed
+	BulkModeBestEffort   = "best_effort"    // save the succeeded items and report the failed items
+)
+
+// BulkParam is the expected parameters for the bulk request.
+type BulkParam struct {
+	Mode     string                  `json:"mode"`
+	Items    []json.RawMessage       `json:"items" validate:"required,min=1"`
+	IsDryRun bool                    `json:"-"` // process all items to report the errors, then roll back everything
+	OnItem   func(result BulkResult) `json:"-"` // called after each item is processed, for example to report the progress
+	isImport bool                    // the import is limited by IMPORT_MAX_ROWS instead of BULK_MAX_ITEMS
+}
+
+// OpenAPISchemaName returns the name of the BulkParam schema in the open api documentation.
+func (BulkParam) OpenAPISchemaName() string {
+	return "BulkParam"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the BulkParam in the open api documentation.
+func (BulkParam) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"mode": map[string]any{
+				"type":    "string",
+				"enum":    []string{BulkModeAllOrNothing, BulkModeBestEffort},
+				"default": BulkModeAllOrNothing,
+			},
+			"items": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+		},
+		"required": []string{"items"},
+	}
+}
+
+// BulkItemResult is the result of each item of the bulk request.
+type BulkItemResult struct {
+	Index   int    `json:"index"`
+	ID      string `json:"id,omitempty"`
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+	Detail  any    `json:"detail,omitempty"`
+}
+
+// BulkResult is the response of the bulk request.
+type BulkResult struct {
+	Mode         string           `json:"mode"`
+	SuccessCount int              `json:"success_count"`
+	FailedCount  int              `json:"failed_count"`
+	Results      []BulkItemResult `json:"results"`
+}
+
+// OpenAPISchemaName returns the name of the BulkResult schema in the open api documentation.
+func (BulkResult) OpenAPISchemaName() string {
+	return "BulkResult"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the BulkResult in the open api documentation.
+func (BulkResult) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"mode":          map[string]any{"type": "string"},
+			"success_count": map[string]any{"type": "integer"},
+			"failed_count":  map[string]any{"type": "integer"},
+			"results": map[string]any{"type": "array", "items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"index":   map[string]any{"type": "integer"},
+					"id":      map[string]any{"type": "string"},
+					"code":    map[string]any{"type": "integer"},
+					"message": map[string]any{"type": "string"},
+					"detail":  map[string]any{"type": "object"},
+				},
+			}},
+		},
+	}
+}
+
+// StatusCode returns the http status code of the bulk request based on the results.
+// It returns successCode if all items are succeeded, 422 if any item is failed on all or nothing mode (the transaction is rolled back)
+// and 207 (multi status) if some items are failed on best effort mode.
+func (r BulkResult) StatusCode(successCode int) int {
+	if r.FailedCount == 0 {
+		return successCode
+	}
+	if r.Mode == BulkModeAllOrNothing {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusMultiStatus
+}
+
+// RunBulk calls fn for each item of the bulk request inside the db transaction of the ctx.
+// Each item runs on its own savepoint, so the failed item is rolled back without aborting the other items.
+// On all or nothing mode, every item is still processed to report all errors, and the caller must roll back the transaction
+// if any item is failed (the db middleware rolls back when the response status code is 4xx).
+// If the ctx has no active transaction, RunBulk begins and ends its own transaction.
+// On dry run, the changes of all items are rolled back after the items are processed.
+// The request with more items than BULK_MAX_ITEMS is rejected.
+func (c Ctx) RunBulk(param BulkParam, fn func(ctx Ctx, item []byte) (id string, err error)) (BulkResult, error) {
+	res := BulkResult{Mode: param.Mode, Results: []BulkItemResult{}}
+	if res.Mode == "" {
+		res.Mode = BulkModeAllOrNothing
+	}
+	if res.Mode != BulkModeAllOrNothing && res.Mode != BulkModeBestEffort {
+		return res, Error().New(http.StatusBadRequest, "mode must be "+BulkModeAllOrNothing+" or "+BulkModeBestEffort)
+	}
+	if err := c.ValidateParam(&param); err != nil {
+		return res, err
+	}
+	if BULK_MAX_ITEMS > 0 && !param.isImport && len(param.Items) > BULK_MAX_ITEMS {
+		return res, Error().New(http.StatusRequestEntityTooLarge, "items must not be more than "+strconv.Itoa(BULK_MAX_ITEMS))
+	}
+
+	c.isBulk = true
+	isOwnTx := false
+	if !IS_USE_MOCK_DB && (c.IsAsync || c.mainTx == nil) {
+		c.IsAsync = false
+		if err := c.TxBegin(); err != nil {
+			return res, Error().New(http.StatusInternalServerError, err.Error())
+		}
+		isOwnTx = true
+	}
+	tx, err := c.DB()
+	if err != nil {
+		return res, Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	dryRunMark := c.afterCommitMark()
+	if param.IsDryRun {
+		err = tx.SavePoint("bulk_dry_run").Error
+	}
+	for i, item := range param.Items {
+		if err != nil {
+			break
+		}
+		savePoint := "bulk_item_" + strconv.Itoa(i)
+		if err = tx.SavePoint(savePoint).Error; err != nil {
+			break
+		}
+		mark := c.afterCommitMark()
+		id, itemErr := fn(c, item)
+		r := BulkItemResult{Index: i, ID: id, Code: http.StatusOK}
+		if itemErr != nil {
+			if err = tx.RollbackTo(savePoint).Error; err != nil {
+				break
+			}
+			c.discardAfterCommit(mark) // for example the job pushed to redis by the rolled back item
+			e, ok := itemErr.(*grest.Error)
+			if !ok {
+				e = Error().New(http.StatusInternalServerError, itemErr.Error())
+			}
+			r.Code = e.StatusCode()
+			r.Message = e.Error()
+			r.Detail = e.Detail
+			if r.Code == http.StatusInternalServerError {
+				r.Message = c.Trans("500_internal_error")
+			}
+			res.FailedCount++
+		} else {
+			res.SuccessCount++
+		}
+		res.Results = append(res.Results, r)
+		if param.OnItem != nil {
+			param.OnItem(res)
+		}
+	}
+	if param.IsDryRun && err == nil {
+		err = tx.RollbackTo("bulk_dry_run").Error
+		c.discardAfterCommit(dryRunMark)
+	}
+
+	if isOwnTx {
+		if err != nil || (res.FailedCount > 0 && res.Mode == BulkModeAllOrNothing) {
+			c.TxRollback()
+		} else {
+			c.TxCommit()
+		}
+	}
+	if err != nil {
+		return res, Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return res, nil
+}
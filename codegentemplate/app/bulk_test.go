@@ -0,0 +1,52 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+	"grest.dev/grest"
+)
+
+func TestDiscardAfterCommit(t *testing.T) {
+	calls := []string{}
+	c := Ctx{mainTx: &gorm.DB{}, afterCommit: &[]func(){}}
+	c.AfterCommit(func() { calls = append(calls, "item_0") })
+
+	// the item is rolled back to its savepoint, so its callbacks are discarded
+	mark := c.afterCommitMark()
+	item := c // the item receives the copy of the ctx, like RunBulk
+	item.AfterCommit(func() { calls = append(calls, "item_1") })
+	item.AfterCommit(func() { calls = append(calls, "item_1.hook") })
+	c.discardAfterCommit(mark)
+
+	c.AfterCommit(func() { calls = append(calls, "item_2") })
+	for _, fn := range *c.afterCommit {
+		fn()
+	}
+	if len(calls) != 2 || calls[0] != "item_0" || calls[1] != "item_2" {
+		t.Errorf("Expected the callbacks of the rolled back item are discarded, got %v", calls)
+	}
+
+	c.discardAfterCommit(10)
+	if len(*c.afterCommit) != 2 {
+		t.Errorf("Expected the mark above the registered callbacks is ignored, got [%v]", len(*c.afterCommit))
+	}
+}
+
+func TestRunBulkMaxItems(t *testing.T) {
+	max := BULK_MAX_ITEMS
+	BULK_MAX_ITEMS = 2
+	defer func() { BULK_MAX_ITEMS = max }()
+
+	items := []json.RawMessage{json.RawMessage(`{}`), json.RawMessage(`{}`), json.RawMessage(`{}`)}
+	_, err := Ctx{}.RunBulk(BulkParam{Items: items}, func(ctx Ctx, item []byte) (string, error) {
+		t.Fatalf("Expected the item is not processed")
+		return "", nil
+	})
+	e, ok := err.(*grest.Error)
+	if !ok || e.StatusCode() != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected error 413, got [%v]", err)
+	}
+}
@@ -2,10 +2,14 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
 	"grest.dev/grest"
 )
 
@@ -28,16 +32,29 @@ var cache *cacheUtil
 // It embeds grest.Cache, indicating that cacheUtil inherits from grest.Cache.
 type cacheUtil struct {
 	grest.Cache
+	mu    *sync.Mutex         // guards SetNX on the in-memory local storage
+	group *singleflight.Group // coalesces the concurrent loads of the same key on Fetch
+
+	instanceID         string        // the id of this instance on the invalidation broadcast
+	invalidationDriver string        // redis, db or none, see StartInvalidation
+	quit               chan struct{} // stops receiving the invalidation broadcast
+
+	local  *localCache // the bounded in-memory local storage, used when the cache is not connected to redis
+	hits   int64
+	misses int64
 }
 
 // configure configures the cache utility instance.
-// It sets the expiration time (c.Exp) to 24 hours and initializes the Redis client (c.RedisClient) with the provided Redis options.
+// It sets the expiration time (c.Exp) to CACHE_TTL and initializes the Redis client (c.RedisClient) with the provided Redis options.
 // It sets the context (c.Ctx) to the background context.
 // It pings the Redis server to check the connection status and stores the result in the err variable.
 // If there is an error connecting to Redis, it logs the error and the Redis connection details.
 // Otherwise, it sets c.IsUseRedis to true and logs a successful cache configuration with Redis.
 func (c *cacheUtil) configure() {
-	c.Exp = 24 * time.Hour
+	c.Exp = CACHE_TTL
+	c.mu = &sync.Mutex{}
+	c.group = &singleflight.Group{}
+	c.local = newLocalCache(CACHE_LOCAL_MAX_ENTRIES, int64(CACHE_LOCAL_MAX_SIZE_MB)*1024*1024)
 	c.RedisClient = redis.NewClient(&redis.Options{
 		Addr:     REDIS_HOST + ":" + REDIS_PORT,
 		Username: REDIS_USERNAME,
@@ -60,3 +77,128 @@ func (c *cacheUtil) configure() {
 		Logger().Info("Cache configured with redis")
 	}
 }
+
+// Close stops receiving the invalidation broadcast and closes the redis client, it is called on graceful shutdown.
+func (c *cacheUtil) Close() error {
+	if c.quit != nil {
+		close(c.quit)
+		c.quit = nil
+	}
+	if c.RedisClient == nil {
+		return nil
+	}
+	return c.RedisClient.Close()
+}
+
+// SetNX sets the value of the key only if the key does not exist, it returns true if the value is set.
+// It is atomic across instances when the cache uses redis, otherwise it is only atomic within this instance.
+func (c *cacheUtil) SetNX(key string, val any, exp time.Duration) (bool, error) {
+	if c.IsUseRedis {
+		b, err := json.Marshal(val)
+		if err != nil {
+			return false, err
+		}
+		return c.RedisClient.SetNX(c.Ctx, key, b, exp).Result()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var existing json.RawMessage
+	if c.Get(key, &existing) == nil && len(existing) > 0 {
+		return false, nil
+	}
+	return true, c.Set(key, val, exp)
+}
+
+// cacheEntry is the cached value of Fetch with the time when it becomes stale.
+type cacheEntry struct {
+	Data    json.RawMessage `json:"data"`
+	StaleAt time.Time       `json:"stale_at"`
+}
+
+// TTL returns the cache ttl and the stale-while-revalidate duration of the model.
+// The model can override the default CACHE_TTL and CACHE_STALE_TTL by implementing CacheTTL() and CacheStaleTTL().
+func (*cacheUtil) TTL(model any) (time.Duration, time.Duration) {
+	ttl, staleTTL := CACHE_TTL, CACHE_STALE_TTL
+	if m, ok := model.(interface{ CacheTTL() time.Duration }); ok {
+		ttl = m.CacheTTL()
+	}
+	if m, ok := model.(interface{ CacheStaleTTL() time.Duration }); ok {
+		staleTTL = m.CacheStaleTTL()
+	}
+	return ttl, staleTTL
+}
+
+// Fetch gets the cached value of the key to val, or loads it with load and caches it with the ttl of the model.
+//
+//   - On miss, the concurrent loads of the same key are coalesced, so only one of them queries the database
+//     and the others wait for its result (within this instance). The load inside the transaction of the ctx
+//     is neither coalesced nor cached, since it may see the uncommitted data.
+//   - After the ttl, the stale value is still returned for the stale-while-revalidate duration
+//     while it is reloaded on the background with the async ctx, so load must use the db of its ctx argument.
+//   - If the request is sent with the Cache-Control: no-cache header, the cached value is ignored and reloaded.
+//   - If the ttl of the model is 0, the value is always loaded and never cached.
+//
+// The error of load is returned as is and never cached.
+func (c *cacheUtil) Fetch(ctx Ctx, key string, model any, val any, load func(ctx Ctx) (any, error)) error {
+	ttl, staleTTL := c.TTL(model)
+	if ttl > 0 && !ctx.IsNoCache {
+		entry := cacheEntry{}
+		if c.Get(key, &entry) == nil && len(entry.Data) > 0 {
+			if time.Now().After(entry.StaleAt) {
+				bg := ctx
+				bg.IsAsync = true // use its own db connection, the transaction of the request may be ended before the reload
+				Server().Go(bg, "Failed to revalidate the cache", func() {
+					c.load(bg, key, ttl, staleTTL, load)
+				})
+			}
+			return json.Unmarshal(entry.Data, val)
+		}
+	}
+	b, err := c.load(ctx, key, ttl, staleTTL, load)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, val)
+}
+
+// GetFetched gets the value cached by Fetch of the key to val, even if it is stale, without loading it on miss.
+func (c *cacheUtil) GetFetched(key string, val any) error {
+	entry := cacheEntry{}
+	if err := c.Get(key, &entry); err != nil {
+		return err
+	}
+	if len(entry.Data) == 0 {
+		return errors.New("key " + key + " is not cached by fetch")
+	}
+	return json.Unmarshal(entry.Data, val)
+}
+
+// load calls the load function once for the concurrent calls of the same key and caches the result.
+// The load inside the transaction of the ctx is called directly and not cached.
+func (c *cacheUtil) load(ctx Ctx, key string, ttl, staleTTL time.Duration, load func(ctx Ctx) (any, error)) ([]byte, error) {
+	if !ctx.IsAsync && ctx.mainTx != nil {
+		val, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(val)
+	}
+	res, err, _ := c.group.Do(key, func() (any, error) {
+		val, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		if ttl > 0 {
+			c.Set(key, cacheEntry{Data: b, StaleAt: time.Now().Add(ttl)}, ttl+staleTTL)
+		}
+		return b, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]byte), nil
+}
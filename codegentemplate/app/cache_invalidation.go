@@ -0,0 +1,221 @@
+package app
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CacheInvalidationChannel is the redis pub/sub channel of the cache invalidation broadcast.
+const CacheInvalidationChannel = "cache_invalidations"
+
+// CacheInvalidation is the invalidation of the cache which is broadcast to the other instances.
+// On db driver, it is saved to the cache_invalidations table and polled by the other instances.
+type CacheInvalidation struct {
+	ID         int64        `json:"-"           gorm:"column:id;primaryKey;autoIncrement"`
+	InstanceID NullString   `json:"instance_id" gorm:"column:instance_id"`
+	Prefix     NullString   `json:"prefix"      gorm:"column:prefix"`
+	Keys       NullText     `json:"keys"        gorm:"column:keys"`
+	CreatedAt  NullDateTime `json:"created_at"  gorm:"column:created_at;index"`
+}
+
+// TableVersion returns the versions of the CacheInvalidation table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (CacheInvalidation) TableVersion() string {
+	return "2026-10-19_15.00"
+}
+
+// TableName returns the name of the CacheInvalidation table in the database.
+func (CacheInvalidation) TableName() string {
+	return "cache_invalidations"
+}
+
+// Invalidate invalidates the cache of the prefix (and the keys), then broadcasts the invalidation to the other instances,
+// so the in-memory local cache of every instance is invalidated too.
+// Inside the transaction of the ctx, both are deferred until the transaction is committed,
+// otherwise the cache can be refilled with the uncommitted old data before the commit.
+func (c *cacheUtil) Invalidate(ctx Ctx, prefix string, keys ...string) error {
+	if ctx.isInTx() {
+		ctx.AfterCommit(func() {
+			if err := c.invalidate(prefix, keys...); err != nil {
+				Logger().Error("Failed to invalidate the cache", slog.String("prefix", prefix), slog.Any("err", err))
+			}
+			c.broadcast(prefix, keys)
+		})
+		return nil
+	}
+	err := c.invalidate(prefix, keys...)
+	c.broadcast(prefix, keys)
+	return err
+}
+
+// StartInvalidation starts to receive the invalidation broadcast from the other instances with CACHE_INVALIDATION_DRIVER :
+//
+//   - redis : the invalidation is published and subscribed on the redis pub/sub, it reconnects when redis is back.
+//   - db : the invalidation is saved to the cache_invalidations table and polled every CACHE_INVALIDATION_POLL_INTERVAL.
+//   - auto : redis if the cache is connected to redis, otherwise db.
+//   - none : the invalidation is not broadcast.
+//
+// The received invalidation is only applied when the cache uses the in-memory local storage, since redis is already shared.
+// Use the same driver on every instance.
+func (c *cacheUtil) StartInvalidation() {
+	c.invalidationDriver = CACHE_INVALIDATION_DRIVER
+	if c.invalidationDriver == "auto" {
+		c.invalidationDriver = "db"
+		if c.IsUseRedis {
+			c.invalidationDriver = "redis"
+		}
+	}
+	hostname, _ := os.Hostname()
+	c.instanceID = hostname + "-" + Crypto().NewToken()[:8]
+	c.quit = make(chan struct{})
+	switch c.invalidationDriver {
+	case "redis":
+		go c.subscribeInvalidation(c.quit)
+	case "db":
+		go c.pollInvalidation(c.quit, newInvalidationPoller(time.Now().UTC()))
+	}
+}
+
+// broadcast sends the invalidation to the other instances.
+func (c *cacheUtil) broadcast(prefix string, keys []string) {
+	if c.quit == nil {
+		return // not started, for example on the cli command
+	}
+	if keys == nil {
+		keys = []string{}
+	}
+	b, _ := json.Marshal(keys)
+	inv := CacheInvalidation{
+		InstanceID: NewNullString(c.instanceID),
+		Prefix:     NewNullString(prefix),
+		Keys:       NewNullText(string(b)),
+		CreatedAt:  NewNullDateTime(time.Now().UTC()),
+	}
+	var err error
+	switch c.invalidationDriver {
+	case "redis":
+		b, _ = json.Marshal(inv)
+		err = c.RedisClient.Publish(c.Ctx, CacheInvalidationChannel, b).Err()
+	case "db":
+		tx, dbErr := DB().Conn("main")
+		if dbErr == nil {
+			dbErr = tx.Create(&inv).Error
+		}
+		err = dbErr
+	}
+	if err != nil {
+		Logger().Error("Failed to broadcast the cache invalidation", slog.String("prefix", prefix), slog.Any("err", err))
+	}
+}
+
+// receive applies the invalidation from the other instance to the in-memory local cache.
+func (c *cacheUtil) receive(inv CacheInvalidation) {
+	if c.IsUseRedis || inv.InstanceID.String == c.instanceID {
+		return
+	}
+	keys := []string{}
+	json.Unmarshal([]byte(inv.Keys.String), &keys)
+	c.invalidate(inv.Prefix.String, keys...)
+}
+
+// subscribeInvalidation receives the invalidation from the redis pub/sub until the cache is closed.
+func (c *cacheUtil) subscribeInvalidation(quit chan struct{}) {
+	ps := c.RedisClient.Subscribe(c.Ctx, CacheInvalidationChannel)
+	defer ps.Close()
+	ch := ps.Channel()
+	for {
+		select {
+		case <-quit:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			c.receiveMessage(msg)
+		}
+	}
+}
+
+func (c *cacheUtil) receiveMessage(msg *redis.Message) {
+	inv := CacheInvalidation{}
+	if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+		Logger().Error("Failed to decode the cache invalidation", slog.Any("err", err))
+		return
+	}
+	c.receive(inv)
+}
+
+// pollInvalidation receives the new invalidation from the cache_invalidations table until the cache is closed,
+// the invalidation older than CACHE_INVALIDATION_RETENTION is deleted.
+func (c *cacheUtil) pollInvalidation(quit chan struct{}, p *invalidationPoller) {
+	cleanedAt := time.Time{}
+	for {
+		select {
+		case <-quit:
+			return
+		case <-time.After(CACHE_INVALIDATION_POLL_INTERVAL):
+		}
+		tx, err := DB().Conn("main")
+		if err != nil {
+			Logger().Error("Failed to poll the cache invalidation", slog.Any("err", err))
+			continue
+		}
+		invs := []CacheInvalidation{}
+		err = tx.Where("created_at >= ?", p.since()).Order("created_at, id").Find(&invs).Error
+		if err != nil {
+			Logger().Error("Failed to poll the cache invalidation", slog.Any("err", err))
+			continue
+		}
+		for _, inv := range p.next(invs) {
+			c.receive(inv)
+		}
+		if time.Since(cleanedAt) > time.Minute {
+			cleanedAt = time.Now()
+			tx.Where("created_at < ?", time.Now().UTC().Add(-CACHE_INVALIDATION_RETENTION)).Delete(&CacheInvalidation{})
+		}
+	}
+}
+
+// invalidationPoller tracks the polled invalidation of the cache_invalidations table.
+// The id is taken before the commit, so the invalidation can be committed out of order,
+// it polls again the invalidation within CACHE_INVALIDATION_POLL_OVERLAP before the last one and skips the received id.
+type invalidationPoller struct {
+	last time.Time           // the created_at of the last received invalidation
+	seen map[int64]time.Time // the received id within the overlap, with its created_at
+}
+
+// newInvalidationPoller returns the invalidationPoller which skips the invalidation before start.
+func newInvalidationPoller(start time.Time) *invalidationPoller {
+	return &invalidationPoller{last: start, seen: map[int64]time.Time{}}
+}
+
+// since returns the created_at to poll from.
+func (p *invalidationPoller) since() time.Time {
+	return p.last.Add(-CACHE_INVALIDATION_POLL_OVERLAP)
+}
+
+// next returns the polled invalidation which is not received yet, and forgets the id which is out of the overlap.
+func (p *invalidationPoller) next(invs []CacheInvalidation) []CacheInvalidation {
+	res := []CacheInvalidation{}
+	for _, inv := range invs {
+		if _, ok := p.seen[inv.ID]; ok {
+			continue
+		}
+		p.seen[inv.ID] = inv.CreatedAt.Time
+		if inv.CreatedAt.Time.After(p.last) {
+			p.last = inv.CreatedAt.Time
+		}
+		res = append(res, inv)
+	}
+	since := p.since()
+	for id, createdAt := range p.seen {
+		if createdAt.Before(since) {
+			delete(p.seen, id)
+		}
+	}
+	return res
+}
@@ -0,0 +1,299 @@
+package app
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCacheMiss is returned by Cache().Get if the key is not found or expired.
+var ErrCacheMiss = errors.New("cache: key is not found")
+
+// CacheKey is the cached key, it is used by the cache admin end point.
+type CacheKey struct {
+	Key       string    `json:"key"`
+	Size      int64     `json:"size"` // in bytes, 0 if it is unknown
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CacheStats is the statistics of the cache, it is used by the cache admin end point.
+type CacheStats struct {
+	IsUseRedis bool       `json:"is_use_redis"`
+	Entries    int        `json:"entries"`     // the in-memory local cache only
+	Bytes      int64      `json:"bytes"`       // the in-memory local cache only
+	MaxEntries int        `json:"max_entries"` // the in-memory local cache only
+	MaxBytes   int64      `json:"max_bytes"`   // the in-memory local cache only
+	Hits       int64      `json:"hits"`
+	Misses     int64      `json:"misses"`
+	Evictions  int64      `json:"evictions"` // the in-memory local cache only
+	Keys       []CacheKey `json:"keys"`
+}
+
+// OpenAPISchemaName returns the name of the CacheStats schema in the open api documentation.
+func (CacheStats) OpenAPISchemaName() string {
+	return "CacheStats"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the CacheStats in the open api documentation.
+func (CacheStats) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"is_use_redis": map[string]any{"type": "boolean"},
+			"entries":      map[string]any{"type": "integer"},
+			"bytes":        map[string]any{"type": "integer"},
+			"max_entries":  map[string]any{"type": "integer"},
+			"max_bytes":    map[string]any{"type": "integer"},
+			"hits":         map[string]any{"type": "integer"},
+			"misses":       map[string]any{"type": "integer"},
+			"evictions":    map[string]any{"type": "integer"},
+			"keys": map[string]any{"type": "array", "items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"key":        map[string]any{"type": "string"},
+					"size":       map[string]any{"type": "integer"},
+					"expires_at": map[string]any{"type": "string", "format": "date-time"},
+				},
+			}},
+		},
+	}
+}
+
+// Get gets the cached value of the key to val, it returns ErrCacheMiss if the key is not found on the local cache.
+func (c *cacheUtil) Get(key string, val any) error {
+	var err error
+	if c.IsUseRedis {
+		err = c.Cache.Get(key, val)
+	} else if b, ok := c.local.get(key); ok {
+		err = json.Unmarshal(b, val)
+	} else {
+		err = ErrCacheMiss
+	}
+	if err != nil {
+		atomic.AddInt64(&c.misses, 1)
+	} else {
+		atomic.AddInt64(&c.hits, 1)
+	}
+	return err
+}
+
+// Set caches the value of the key with the expiration (default c.Exp).
+func (c *cacheUtil) Set(key string, val any, exp ...time.Duration) error {
+	if c.IsUseRedis {
+		return c.Cache.Set(key, val, exp...)
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	e := c.Exp
+	if len(exp) > 0 {
+		e = exp[0]
+	}
+	c.local.set(key, b, e)
+	return nil
+}
+
+// Delete deletes the cached value of the key.
+func (c *cacheUtil) Delete(key string) error {
+	if c.IsUseRedis {
+		return c.Cache.Delete(key)
+	}
+	c.local.delete(key)
+	return nil
+}
+
+// invalidate invalidates the cache of the prefix (and the keys) on this instance,
+// the local cache deletes all keys of the prefix.
+func (c *cacheUtil) invalidate(prefix string, keys ...string) error {
+	if c.IsUseRedis {
+		return c.Cache.Invalidate(prefix, keys...)
+	}
+	c.local.deletePrefix(prefix)
+	return nil
+}
+
+// Stats returns the statistics of the cache with the keys of the prefix, up to limit keys.
+func (c *cacheUtil) Stats(prefix string, limit int) (CacheStats, error) {
+	stats := CacheStats{
+		IsUseRedis: c.IsUseRedis,
+		Hits:       atomic.LoadInt64(&c.hits),
+		Misses:     atomic.LoadInt64(&c.misses),
+		Keys:       []CacheKey{},
+	}
+	if !c.IsUseRedis {
+		stats.Entries, stats.Bytes, stats.Evictions = c.local.stats()
+		stats.MaxEntries, stats.MaxBytes = c.local.maxEntries, c.local.maxBytes
+		stats.Keys = c.local.keys(prefix, limit)
+		return stats, nil
+	}
+	iter := c.RedisClient.Scan(c.Ctx, 0, cacheMatch(prefix), 100).Iterator()
+	for len(stats.Keys) < limit && iter.Next(c.Ctx) {
+		key := CacheKey{Key: iter.Val()}
+		if ttl, err := c.RedisClient.PTTL(c.Ctx, key.Key).Result(); err == nil && ttl > 0 {
+			key.ExpiresAt = time.Now().Add(ttl)
+		}
+		if size, err := c.RedisClient.MemoryUsage(c.Ctx, key.Key).Result(); err == nil {
+			key.Size = size
+		}
+		stats.Keys = append(stats.Keys, key)
+	}
+	return stats, iter.Err()
+}
+
+// Flush deletes all cached keys of the prefix, then broadcasts the invalidation to the other instances.
+// It returns the number of deleted keys on this instance.
+func (c *cacheUtil) Flush(prefix string) (int64, error) {
+	count := int64(0)
+	if c.IsUseRedis {
+		iter := c.RedisClient.Scan(c.Ctx, 0, cacheMatch(prefix), 100).Iterator()
+		for iter.Next(c.Ctx) {
+			n, err := c.RedisClient.Del(c.Ctx, iter.Val()).Result()
+			if err != nil {
+				return count, err
+			}
+			count += n
+		}
+		if err := iter.Err(); err != nil {
+			return count, err
+		}
+	} else {
+		count = int64(c.local.deletePrefix(prefix))
+	}
+	c.broadcast(prefix, nil)
+	return count, nil
+}
+
+// cacheMatch returns the redis match pattern of the keys with the prefix.
+func cacheMatch(prefix string) string {
+	r := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return r.Replace(prefix) + "*"
+}
+
+// localCache is the bounded in-memory cache with LRU eviction, it is used when the cache is not connected to redis.
+// The least recently used entry is evicted when the number of entries exceeds maxEntries or the size exceeds maxBytes,
+// set them to 0 for no limit.
+type localCache struct {
+	mu         sync.Mutex
+	ll         *list.List // the most recently used entry is on the front
+	items      map[string]*list.Element
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	evictions  int64
+}
+
+// localCacheEntry is the entry of the localCache.
+type localCacheEntry struct {
+	key string
+	val []byte
+	exp time.Time
+}
+
+func (e *localCacheEntry) size() int64 {
+	return int64(len(e.key) + len(e.val))
+}
+
+func newLocalCache(maxEntries int, maxBytes int64) *localCache {
+	return &localCache{
+		ll:         list.New(),
+		items:      map[string]*list.Element{},
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+	}
+}
+
+func (l *localCache) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*localCacheEntry)
+	if !e.exp.IsZero() && time.Now().After(e.exp) {
+		l.remove(el)
+		return nil, false
+	}
+	l.ll.MoveToFront(el)
+	return e.val, true
+}
+
+func (l *localCache) set(key string, val []byte, exp time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := &localCacheEntry{key: key, val: val}
+	if exp > 0 {
+		e.exp = time.Now().Add(exp)
+	}
+	if el, ok := l.items[key]; ok {
+		l.bytes += e.size() - el.Value.(*localCacheEntry).size()
+		el.Value = e
+		l.ll.MoveToFront(el)
+	} else {
+		l.items[key] = l.ll.PushFront(e)
+		l.bytes += e.size()
+	}
+	for l.ll.Len() > 0 && ((l.maxEntries > 0 && l.ll.Len() > l.maxEntries) || (l.maxBytes > 0 && l.bytes > l.maxBytes)) {
+		l.remove(l.ll.Back())
+		l.evictions++
+	}
+}
+
+func (l *localCache) delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		l.remove(el)
+	}
+}
+
+func (l *localCache) deletePrefix(prefix string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	count := 0
+	for key, el := range l.items {
+		if strings.HasPrefix(key, prefix) {
+			l.remove(el)
+			count++
+		}
+	}
+	return count
+}
+
+func (l *localCache) remove(el *list.Element) {
+	e := el.Value.(*localCacheEntry)
+	l.ll.Remove(el)
+	delete(l.items, e.key)
+	l.bytes -= e.size()
+}
+
+func (l *localCache) stats() (int, int64, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len(), l.bytes, l.evictions
+}
+
+// keys returns the unexpired keys of the prefix sorted by key, up to limit keys.
+func (l *localCache) keys(prefix string, limit int) []CacheKey {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	keys := []CacheKey{}
+	now := time.Now()
+	for key, el := range l.items {
+		e := el.Value.(*localCacheEntry)
+		if strings.HasPrefix(key, prefix) && (e.exp.IsZero() || now.Before(e.exp)) {
+			keys = append(keys, CacheKey{Key: key, Size: e.size(), ExpiresAt: e.exp})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type cacheTestModel struct{}
+
+func (cacheTestModel) CacheTTL() time.Duration {
+	return time.Minute
+}
+
+func TestCacheFetch(t *testing.T) {
+	if ttl, staleTTL := Cache().TTL(cacheTestModel{}); ttl != time.Minute || staleTTL != CACHE_STALE_TTL {
+		t.Errorf("Expected ttl [1m %v], got [%v %v]", CACHE_STALE_TTL, ttl, staleTTL)
+	}
+
+	calls := int32(0)
+	start := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			res := map[string]int{}
+			err := Cache().Fetch(Ctx{}, "cache_test.fetch", cacheTestModel{}, &res, func(ctx Ctx) (any, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(50 * time.Millisecond)
+				return map[string]int{"count": 1}, nil
+			})
+			if err != nil || res["count"] != 1 {
+				t.Errorf("Expected fetched value, got [%v] [%v]", res, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("Expected the concurrent loads are coalesced to 1 call, got [%v]", calls)
+	}
+
+	// the stale value is returned and reloaded on the background with the async ctx
+	Cache().Set("cache_test.stale", cacheEntry{Data: []byte(`{"count":1}`), StaleAt: time.Now().Add(-time.Second)}, time.Minute)
+	reloaded := make(chan bool, 1)
+	res := map[string]int{}
+	err := Cache().Fetch(Ctx{}, "cache_test.stale", cacheTestModel{}, &res, func(ctx Ctx) (any, error) {
+		reloaded <- ctx.IsAsync
+		return map[string]int{"count": 2}, nil
+	})
+	if err != nil || res["count"] != 1 {
+		t.Errorf("Expected the stale value, got [%v] [%v]", res, err)
+	}
+	select {
+	case isAsync := <-reloaded:
+		if !isAsync {
+			t.Errorf("Expected the background reload uses the async ctx")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the stale value is reloaded on the background")
+	}
+	for i := 0; i < 100 && res["count"] != 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		err = Cache().GetFetched("cache_test.stale", &res)
+	}
+	if err != nil || res["count"] != 2 {
+		t.Errorf("Expected the reloaded value is cached, got [%v] [%v]", res, err)
+	}
+}
+
+func TestLocalCache(t *testing.T) {
+	l := newLocalCache(3, 0)
+	l.set("a.1", []byte("1"), time.Minute)
+	l.set("a.2", []byte("2"), time.Minute)
+	l.set("b.1", []byte("3"), time.Minute)
+	l.get("a.1") // a.1 is recently used, so a.2 is evicted
+	l.set("b.2", []byte("4"), time.Minute)
+	if _, ok := l.get("a.2"); ok {
+		t.Errorf("Expected a.2 is evicted")
+	}
+	if entries, bytes, evictions := l.stats(); entries != 3 || bytes != 12 || evictions != 1 {
+		t.Errorf("Expected stats [3 12 1], got [%v %v %v]", entries, bytes, evictions)
+	}
+	if keys := l.keys("b.", 10); len(keys) != 2 || keys[0].Key != "b.1" {
+		t.Errorf("Expected keys [b.1 b.2], got %v", keys)
+	}
+	if count := l.deletePrefix("b."); count != 2 {
+		t.Errorf("Expected 2 deleted keys, got [%v]", count)
+	}
+
+	l = newLocalCache(0, 9)
+	l.set("a", []byte("1234"), 0)
+	l.set("b", []byte("1234"), 0)
+	if _, ok := l.get("a"); ok {
+		t.Errorf("Expected a is evicted by size")
+	}
+	l.set("c", []byte("1"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := l.get("c"); ok {
+		t.Errorf("Expected c is expired")
+	}
+}
+
+func TestInvalidationPoller(t *testing.T) {
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	p := newInvalidationPoller(start)
+	inv := func(id int64, sec int) CacheInvalidation {
+		return CacheInvalidation{ID: id, CreatedAt: NewNullDateTime(start.Add(time.Duration(sec) * time.Second))}
+	}
+
+	ids := func(invs []CacheInvalidation) []int64 {
+		res := []int64{}
+		for _, inv := range invs {
+			res = append(res, inv.ID)
+		}
+		return res
+	}
+
+	// id 2 is taken before id 3, but committed after it
+	got := ids(p.next([]CacheInvalidation{inv(1, 1), inv(3, 2)}))
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("Expected [1 3], got %v", got)
+	}
+	if !p.since().Equal(start.Add(2*time.Second - CACHE_INVALIDATION_POLL_OVERLAP)) {
+		t.Errorf("Expected since within the overlap of the last invalidation, got %v", p.since())
+	}
+	got = ids(p.next([]CacheInvalidation{inv(1, 1), inv(2, 1), inv(3, 2)}))
+	if len(got) != 1 || got[0] != 2 {
+		t.Fatalf("Expected the out of order [2] only, got %v", got)
+	}
+
+	// the id out of the overlap is forgotten
+	p.next([]CacheInvalidation{inv(4, 2+int(CACHE_INVALIDATION_POLL_OVERLAP/time.Second)+1)})
+	if _, ok := p.seen[1]; ok {
+		t.Errorf("Expected the id out of the overlap is forgotten")
+	}
+}
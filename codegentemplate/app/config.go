@@ -1,22 +1,32 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"time"
-
-	"github.com/joho/godotenv"
-	"grest.dev/grest"
 )
 
 // Config initializes the configuration (config) if it is not already initialized.
 // If config is not initialized, it creates a new configUtil instance, configures it, and assigns it to config.
 // It ensures that only one instance of config is created and reused.
-func Config() {
+// The app exits on startup when the configuration is invalid, see validate.
+func Config() *configUtil {
 	if config == nil {
 		config = &configUtil{}
 		config.configure()
+		errs := config.validate()
+		for _, w := range config.warnings {
+			fmt.Fprintln(os.Stderr, "config: "+w)
+		}
+		if len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Fprintln(os.Stderr, "config: "+err.Error())
+			}
+			os.Exit(1)
+		}
 		config.isConfigured = true
 	}
+	return config
 }
 
 // These variables represent various configuration settings used by the application.
@@ -28,12 +38,14 @@ var (
 	APP_PORT = "4001"
 	APP_URL  = "http://localhost:4001"
 
-	IS_MAIN_SERVER = true // set to true to run migration, seed and task scheduling
+	IS_MAIN_SERVER = true // set to true to run migration and seed
 
 	IS_GENERATE_OPEN_API_DOC = false
 
+	SHUTDOWN_TIMEOUT = 30 * time.Second // on .env = "30s". max time to wait for in-flight requests and background process on shutdown
+
 	// for testing
-	ENV_FILE            = ""
+	ENV_FILE            = "" // the absolute path of the .env file, it replaces the .env on the working directory
 	IS_USE_MOCK_SERVICE = false
 	IS_USE_MOCK_DB      = false
 
@@ -54,11 +66,26 @@ var (
 	LOG_WITH_REQUEST_BODY     = true
 	LOG_WITH_RESPONSE_BODY    = true
 
+	LOG_QUEUE_SIZE         = 10000  // the log is written asynchronously through a queue of this size, 0 to write synchronously
+	LOG_QUEUE_POLICY       = "drop" // drop or block. the log when the queue is full is dropped or waits until the queue has space, the error log is never dropped
+	LOG_BATCH_SIZE         = 100    // the queued log is written in batches of this size
+	LOG_ACCESS_SAMPLE_RATE = 1.0    // 0 to 1. the ratio of the successful (2xx) access log which is written, for example 0.1 writes 10% of them
+
+	LOG_REDACT_FIELDS     = "password,passwd,secret,token,authorization,cookie,api_key,apikey,private_key,access_key,secret_key,pin,cvv,card_number" // the log attributes and json body keys which value is masked, it also matches the key with the suffix, for example db_password
+	LOG_REDACT_HEADERS    = "Authorization,Proxy-Authorization,Cookie,Set-Cookie,X-Api-Key"                                                          // the request headers which value is masked
+	LOG_REDACT_BODY_PATHS = ""                                                                                                                       // on .env = "data.card.number,items.*.code". the json body paths which value is masked, * matches any key or array item
+
 	JWT_KEY     = "f4cac8b77a8d4cb5881fac72388bb226"
 	CRYPTO_KEY  = "wAGyTpFQX5uKV3JInABXXEdpgFkQLPTf"
 	CRYPTO_SALT = "0de0cda7d2dd4937a1c4f7ddc43c580f"
 	CRYPTO_INFO = "info"
 
+	CRYPTO_KEY_ID            = "1"                                // the id of CRYPTO_KEY which is prefixed to the ciphertext, change it with the new CRYPTO_KEY to rotate the key
+	CRYPTO_PREFIX            = ""                                 // the prefix of the ciphertext before the key id
+	CRYPTO_RETIRED_KEYS      = ""                                 // on .env = "id:key,id:key". the retired keys, only used to decrypt the ciphertext until it is rotated
+	CRYPTO_ROTATE_BATCH_SIZE = 500                                // the encrypted columns are re-encrypted in batches of this size by `go run main.go crypto rotate`
+	CRYPTO_BLIND_INDEX_KEY   = "5a0f3b9e1c7d4e2f8a6b0c9d3e1f7a2b" // the key of the blind index of the encrypted field, changing it requires the blind index to be recomputed
+
 	DB_DRIVER            = "postgres"
 	DB_HOST              = "127.0.0.1"
 	DB_HOST_READ         = ""
@@ -88,8 +115,77 @@ var (
 	FS_ACCESS_KEY      = ""
 	FS_SECRET_KEY      = ""
 
+	ALERT_NOTIFIERS = "telegram"       // telegram, slack, webhook or email separated by comma. the log is alerted to these notifiers
+	ALERT_LEVEL     = "error"          // debug, info, warning, error. the default level of the log which is alerted, override it per notifier with {NOTIFIER}_ALERT_LEVEL
+	ALERT_ENVS      = ""               // on .env = "production,staging". the alert is only sent on these APP_ENV, empty for every env
+	ALERT_TIMEOUT   = 10 * time.Second // on .env = "10s". the timeout to send the alert to each notifier
+
+	ALERT_THROTTLE_WINDOW        = 5 * time.Minute  // on .env = "5m". the duplicate alerts (same message, path and error type) within this window are sent once with "repeated N times" summary, 0 to disable
+	ALERT_REQUEST_ERRORS         = true             // set to false to alert the failed requests (5xx) only by ALERT_ERROR_RATE_THRESHOLD instead of per error
+	ALERT_THRESHOLD_WINDOW       = time.Minute      // on .env = "1m". the window of ALERT_ERROR_RATE_THRESHOLD and ALERT_P95_LATENCY_THRESHOLD
+	ALERT_THRESHOLD_MIN_REQUESTS = 20               // the window with less requests than this is not alerted by the thresholds
+	ALERT_ERROR_RATE_THRESHOLD   = 0.0              // 0 to 1. alert when the ratio of the failed requests (5xx) in the window is above this, 0 to disable
+	ALERT_P95_LATENCY_THRESHOLD  = time.Duration(0) // on .env = "2s". alert when the p95 latency of the requests in the window is above this, 0 to disable
+
 	TELEGRAM_ALERT_TOKEN   = ""
 	TELEGRAM_ALERT_USER_ID = ""
+	TELEGRAM_ALERT_API_URL = "https://api.telegram.org"
+	TELEGRAM_ALERT_LEVEL   = ""
+
+	SLACK_ALERT_WEBHOOK_URL = "" // the slack compatible incoming webhook url
+	SLACK_ALERT_LEVEL       = ""
+
+	WEBHOOK_ALERT_URL    = "" // the alert is posted as json to this url
+	WEBHOOK_ALERT_SECRET = "" // the alert is signed with this secret on X-Webhook-Signature header, the same way as the webhook subscription
+	WEBHOOK_ALERT_LEVEL  = ""
+
+	EMAIL_ALERT_SMTP_HOST = ""
+	EMAIL_ALERT_SMTP_PORT = 587
+	EMAIL_ALERT_USERNAME  = "" // the smtp auth is skipped if it is empty
+	EMAIL_ALERT_PASSWORD  = ""
+	EMAIL_ALERT_FROM      = ""
+	EMAIL_ALERT_TO        = "" // on .env = "ops@example.com,dev@example.com"
+	EMAIL_ALERT_LEVEL     = ""
+
+	IS_REQUIRE_IF_MATCH = false // set to true to reject PUT, PATCH and DELETE request without If-Match header with 428
+
+	BULK_MAX_ITEMS         = 1000   // the bulk request with more items than this is rejected with 413, 0 for no limit
+	EXPORT_BATCH_SIZE      = 1000   // the exported list is queried and written in batches of this size
+	EXPORT_ESCAPE_FORMULA  = true   // the exported text starting with =, +, -, @, tab or carriage return is prefixed with ' so the spreadsheet doesn't run it as a formula
+	IMPORT_ASYNC_THRESHOLD = 1000   // the imported file with more rows than this is processed on the background job
+	IMPORT_MAX_SIZE_MB     = 20     // the imported file larger than this is rejected, 0 for no limit
+	IMPORT_MAX_ROWS        = 100000 // the imported file with more rows than this (excluding the header) is rejected, 0 for no limit
+	IMPORT_MAX_COLUMNS     = 1000   // the imported file with more columns than this is rejected, 0 for no limit
+
+	CACHE_TTL       = 24 * time.Hour  // on .env = "24h". the default ttl of the cached data, override it with CacheTTL() on the model
+	CACHE_STALE_TTL = 1 * time.Minute // on .env = "1m". the expired cached data is still returned for this duration while it is reloaded on the background
+
+	CACHE_LOCAL_MAX_ENTRIES = 10000 // the in-memory local cache evicts the least recently used data above this number of entries, 0 for no limit
+	CACHE_LOCAL_MAX_SIZE_MB = 256   // the in-memory local cache evicts the least recently used data above this size, 0 for no limit
+
+	CACHE_INVALIDATION_DRIVER        = "auto"           // auto, redis, db or none. the cache invalidation is broadcast to the in-memory local cache of the other instances
+	CACHE_INVALIDATION_POLL_INTERVAL = time.Second      // on .env = "1s". the interval to poll the cache_invalidations table on db driver
+	CACHE_INVALIDATION_RETENTION     = 1 * time.Hour    // on .env = "1h". the polled cache invalidation is deleted after this duration
+	CACHE_INVALIDATION_POLL_OVERLAP  = 10 * time.Second // on .env = "10s". the db driver polls again the invalidation created within this duration before the last one, so the invalidation committed out of order is not skipped
+
+	IDEMPOTENCY_TTL          = 24 * time.Hour  // on .env = "24h". the response of the POST request with Idempotency-Key header is replayed for the retry within this duration
+	IDEMPOTENCY_LOCK_TIMEOUT = 1 * time.Minute // the request with the same Idempotency-Key is rejected with 409 while the first request is being processed, up to this duration
+
+	TRASH_RETENTION_DAYS = 30 // the deleted data is purged after this days, set to 0 to keep the deleted data forever
+
+	SCHEDULER_LOCK_TIMEOUT = time.Hour        // the lock of the running scheduled task is released after this duration, in case the instance is died
+	SCHEDULER_LOCK_GRACE   = 30 * time.Second // the lock is kept after the scheduled time, so the other instance with slightly different clock doesn't run the same schedule
+
+	JOB_STORE          = "db" // db or redis
+	JOB_WORKERS        = 4    // set to 0 to disable running the jobs on this server
+	JOB_POLL_INTERVAL  = time.Second
+	JOB_MAX_ATTEMPTS   = 5
+	JOB_RETRY_INTERVAL = 10 * time.Second   // on .env = "10s", doubled on every retry
+	JOB_LOCK_TIMEOUT   = 5 * time.Minute    // the running job is claimed again after this duration, in case the worker is died
+	JOB_RETENTION      = 7 * 24 * time.Hour // on .env = "168h". the done and dead jobs are deleted after this duration by the jobs.purge scheduled task, 0 to keep them forever
+
+	WEBHOOK_MAX_ATTEMPTS = 5 // the failed delivery is retried by the job queue with the backoff of JOB_RETRY_INTERVAL
+	WEBHOOK_TIMEOUT      = 10 * time.Second
 )
 
 // config is a pointer to a configUtil instance.
@@ -99,79 +195,159 @@ var config *configUtil
 // configUtil represents the application's configuration utility.
 type configUtil struct {
 	isConfigured bool
+	vars         []configVar       // the loaded variables in the loading order
+	sources      map[string]string // the source of the environment variables by key
+	fileKeys     map[string]string // the keys of the .env files, used to warn the unknown key
+	errs         []error           // the malformed values found on loading
+	warnings     []string
 }
 
-// configure configures the application's settings by loading values from environment variables using the grest.LoadEnv function.
+// configure configures the application's settings by loading values from environment variables using the loadEnv function.
 // Each configuration setting is loaded from the corresponding environment variable and assigned to the appropriate variable.
-// The godotenv package is used to load the .env file if provided.
-func (*configUtil) configure() {
-
-	// set ENV_FILE with absolute path for the .env file to run test with .env
-	envFile := os.Getenv("ENV_FILE")
-	if envFile != "" {
-		godotenv.Load(envFile)
-	} else {
-		godotenv.Load()
-	}
+// The .env files are loaded first, see loadEnvFiles.
+func (c *configUtil) configure() {
+	c.loadEnvFiles()
+
+	c.loadEnv("APP_ENV", &APP_ENV)
+	c.loadEnv("APP_PORT", &APP_PORT)
+	c.loadEnv("APP_URL", &APP_URL)
+
+	c.loadEnv("IS_MAIN_SERVER", &IS_MAIN_SERVER)
+	c.loadEnv("SHUTDOWN_TIMEOUT", &SHUTDOWN_TIMEOUT)
+
+	c.loadEnv("ENV_FILE", &ENV_FILE)
+	c.loadEnv("IS_USE_MOCK_SERVICE", &IS_USE_MOCK_SERVICE)
+	c.loadEnv("IS_USE_MOCK_DB", &IS_USE_MOCK_DB)
+
+	c.loadEnv("LOG_LEVEL", &LOG_LEVEL)
+	c.loadEnv("LOG_CONSOLE_ENABLED", &LOG_CONSOLE_ENABLED)
+	c.loadEnv("LOG_CONSOLE_WITH_JSON", &LOG_CONSOLE_WITH_JSON)
+	c.loadEnv("LOG_CONSOLE_TIME_FORMAT", &LOG_CONSOLE_TIME_FORMAT)
+	c.loadEnv("LOG_CONSOLE_EXCLUDED_KEYS", &LOG_CONSOLE_EXCLUDED_KEYS)
+	c.loadEnv("LOG_FILE_ENABLED", &LOG_FILE_ENABLED)
+	c.loadEnv("LOG_FILE_WITH_JSON", &LOG_FILE_WITH_JSON)
+	c.loadEnv("LOG_FILE_USE_LOCAL_TIME", &LOG_FILE_USE_LOCAL_TIME)
+	c.loadEnv("LOG_FILE_FILENAME", &LOG_FILE_FILENAME)
+	c.loadEnv("LOG_FILE_MAX_SIZE", &LOG_FILE_MAX_SIZE)
+	c.loadEnv("LOG_FILE_MAX_AGE", &LOG_FILE_MAX_AGE)
+	c.loadEnv("LOG_FILE_MAX_BACKUPS", &LOG_FILE_MAX_BACKUPS)
+	c.loadEnv("LOG_WITH_DURATION", &LOG_WITH_DURATION)
+	c.loadEnv("LOG_WITH_REQUEST_HEADER", &LOG_WITH_REQUEST_HEADER)
+	c.loadEnv("LOG_WITH_REQUEST_BODY", &LOG_WITH_REQUEST_BODY)
+	c.loadEnv("LOG_WITH_RESPONSE_BODY", &LOG_WITH_RESPONSE_BODY)
+	c.loadEnv("LOG_QUEUE_SIZE", &LOG_QUEUE_SIZE)
+	c.loadEnv("LOG_QUEUE_POLICY", &LOG_QUEUE_POLICY)
+	c.loadEnv("LOG_BATCH_SIZE", &LOG_BATCH_SIZE)
+	c.loadEnv("LOG_ACCESS_SAMPLE_RATE", &LOG_ACCESS_SAMPLE_RATE)
+	c.loadEnv("LOG_REDACT_FIELDS", &LOG_REDACT_FIELDS)
+	c.loadEnv("LOG_REDACT_HEADERS", &LOG_REDACT_HEADERS)
+	c.loadEnv("LOG_REDACT_BODY_PATHS", &LOG_REDACT_BODY_PATHS)
+
+	c.loadEnv("JWT_KEY", &JWT_KEY)
+	c.loadEnv("CRYPTO_KEY", &CRYPTO_KEY)
+	c.loadEnv("CRYPTO_SALT", &CRYPTO_SALT)
+	c.loadEnv("CRYPTO_INFO", &CRYPTO_INFO)
+	c.loadEnv("CRYPTO_KEY_ID", &CRYPTO_KEY_ID)
+	c.loadEnv("CRYPTO_PREFIX", &CRYPTO_PREFIX)
+	c.loadEnv("CRYPTO_RETIRED_KEYS", &CRYPTO_RETIRED_KEYS)
+	c.loadEnv("CRYPTO_ROTATE_BATCH_SIZE", &CRYPTO_ROTATE_BATCH_SIZE)
+	c.loadEnv("CRYPTO_BLIND_INDEX_KEY", &CRYPTO_BLIND_INDEX_KEY)
+
+	c.loadEnv("DB_DRIVER", &DB_DRIVER)
+	c.loadEnv("DB_HOST", &DB_HOST)
+	c.loadEnv("DB_HOST_READ", &DB_HOST_READ)
+	c.loadEnv("DB_PORT", &DB_PORT)
+	c.loadEnv("DB_DATABASE", &DB_DATABASE)
+	c.loadEnv("DB_USERNAME", &DB_USERNAME)
+	c.loadEnv("DB_PASSWORD", &DB_PASSWORD)
+	c.loadEnv("DB_MAX_OPEN_CONNS", &DB_MAX_OPEN_CONNS)
+	c.loadEnv("DB_MAX_IDLE_CONNS", &DB_MAX_IDLE_CONNS)
+	c.loadEnv("DB_CONN_MAX_LIFETIME", &DB_CONN_MAX_LIFETIME)
+	c.loadEnv("DB_IS_DEBUG", &DB_IS_DEBUG)
+
+	c.loadEnv("REDIS_HOST", &REDIS_HOST)
+	c.loadEnv("REDIS_PORT", &REDIS_PORT)
+	c.loadEnv("REDIS_CACHE_DB", &REDIS_CACHE_DB)
+	c.loadEnv("REDIS_REPORT_DB", &REDIS_REPORT_DB)
+	c.loadEnv("REDIS_USERNAME", &REDIS_USERNAME)
+	c.loadEnv("REDIS_PASSWORD", &REDIS_PASSWORD)
+
+	c.loadEnv("FS_DRIVER", &FS_DRIVER)
+	c.loadEnv("FS_LOCAL_DIR_PATH", &FS_LOCAL_DIR_PATH)
+	c.loadEnv("FS_PUBLIC_DIR_PATH", &FS_PUBLIC_DIR_PATH)
+	c.loadEnv("FS_END_POINT", &FS_END_POINT)
+	c.loadEnv("FS_PORT", &FS_PORT)
+	c.loadEnv("FS_REGION", &FS_REGION)
+	c.loadEnv("FS_BUCKET_NAME", &FS_BUCKET_NAME)
+	c.loadEnv("FS_ACCESS_KEY", &FS_ACCESS_KEY)
+	c.loadEnv("FS_SECRET_KEY", &FS_SECRET_KEY)
+
+	c.loadEnv("ALERT_NOTIFIERS", &ALERT_NOTIFIERS)
+	c.loadEnv("ALERT_LEVEL", &ALERT_LEVEL)
+	c.loadEnv("ALERT_ENVS", &ALERT_ENVS)
+	c.loadEnv("ALERT_TIMEOUT", &ALERT_TIMEOUT)
+	c.loadEnv("ALERT_THROTTLE_WINDOW", &ALERT_THROTTLE_WINDOW)
+	c.loadEnv("ALERT_REQUEST_ERRORS", &ALERT_REQUEST_ERRORS)
+	c.loadEnv("ALERT_THRESHOLD_WINDOW", &ALERT_THRESHOLD_WINDOW)
+	c.loadEnv("ALERT_THRESHOLD_MIN_REQUESTS", &ALERT_THRESHOLD_MIN_REQUESTS)
+	c.loadEnv("ALERT_ERROR_RATE_THRESHOLD", &ALERT_ERROR_RATE_THRESHOLD)
+	c.loadEnv("ALERT_P95_LATENCY_THRESHOLD", &ALERT_P95_LATENCY_THRESHOLD)
+
+	c.loadEnv("TELEGRAM_ALERT_TOKEN", &TELEGRAM_ALERT_TOKEN)
+	c.loadEnv("TELEGRAM_ALERT_USER_ID", &TELEGRAM_ALERT_USER_ID)
+	c.loadEnv("TELEGRAM_ALERT_API_URL", &TELEGRAM_ALERT_API_URL)
+	c.loadEnv("TELEGRAM_ALERT_LEVEL", &TELEGRAM_ALERT_LEVEL)
+
+	c.loadEnv("SLACK_ALERT_WEBHOOK_URL", &SLACK_ALERT_WEBHOOK_URL)
+	c.loadEnv("SLACK_ALERT_LEVEL", &SLACK_ALERT_LEVEL)
+
+	c.loadEnv("WEBHOOK_ALERT_URL", &WEBHOOK_ALERT_URL)
+	c.loadEnv("WEBHOOK_ALERT_SECRET", &WEBHOOK_ALERT_SECRET)
+	c.loadEnv("WEBHOOK_ALERT_LEVEL", &WEBHOOK_ALERT_LEVEL)
+
+	c.loadEnv("EMAIL_ALERT_SMTP_HOST", &EMAIL_ALERT_SMTP_HOST)
+	c.loadEnv("EMAIL_ALERT_SMTP_PORT", &EMAIL_ALERT_SMTP_PORT)
+	c.loadEnv("EMAIL_ALERT_USERNAME", &EMAIL_ALERT_USERNAME)
+	c.loadEnv("EMAIL_ALERT_PASSWORD", &EMAIL_ALERT_PASSWORD)
+	c.loadEnv("EMAIL_ALERT_FROM", &EMAIL_ALERT_FROM)
+	c.loadEnv("EMAIL_ALERT_TO", &EMAIL_ALERT_TO)
+	c.loadEnv("EMAIL_ALERT_LEVEL", &EMAIL_ALERT_LEVEL)
+
+	c.loadEnv("IS_REQUIRE_IF_MATCH", &IS_REQUIRE_IF_MATCH)
+
+	c.loadEnv("BULK_MAX_ITEMS", &BULK_MAX_ITEMS)
+	c.loadEnv("EXPORT_BATCH_SIZE", &EXPORT_BATCH_SIZE)
+	c.loadEnv("EXPORT_ESCAPE_FORMULA", &EXPORT_ESCAPE_FORMULA)
+	c.loadEnv("IMPORT_ASYNC_THRESHOLD", &IMPORT_ASYNC_THRESHOLD)
+	c.loadEnv("IMPORT_MAX_SIZE_MB", &IMPORT_MAX_SIZE_MB)
+	c.loadEnv("IMPORT_MAX_ROWS", &IMPORT_MAX_ROWS)
+	c.loadEnv("IMPORT_MAX_COLUMNS", &IMPORT_MAX_COLUMNS)
+
+	c.loadEnv("CACHE_TTL", &CACHE_TTL)
+	c.loadEnv("CACHE_STALE_TTL", &CACHE_STALE_TTL)
+	c.loadEnv("CACHE_LOCAL_MAX_ENTRIES", &CACHE_LOCAL_MAX_ENTRIES)
+	c.loadEnv("CACHE_LOCAL_MAX_SIZE_MB", &CACHE_LOCAL_MAX_SIZE_MB)
+	c.loadEnv("CACHE_INVALIDATION_DRIVER", &CACHE_INVALIDATION_DRIVER)
+	c.loadEnv("CACHE_INVALIDATION_POLL_INTERVAL", &CACHE_INVALIDATION_POLL_INTERVAL)
+	c.loadEnv("CACHE_INVALIDATION_RETENTION", &CACHE_INVALIDATION_RETENTION)
+	c.loadEnv("CACHE_INVALIDATION_POLL_OVERLAP", &CACHE_INVALIDATION_POLL_OVERLAP)
+
+	c.loadEnv("IDEMPOTENCY_TTL", &IDEMPOTENCY_TTL)
+	c.loadEnv("IDEMPOTENCY_LOCK_TIMEOUT", &IDEMPOTENCY_LOCK_TIMEOUT)
+
+	c.loadEnv("TRASH_RETENTION_DAYS", &TRASH_RETENTION_DAYS)
+
+	c.loadEnv("SCHEDULER_LOCK_TIMEOUT", &SCHEDULER_LOCK_TIMEOUT)
+	c.loadEnv("SCHEDULER_LOCK_GRACE", &SCHEDULER_LOCK_GRACE)
+
+	c.loadEnv("JOB_STORE", &JOB_STORE)
+	c.loadEnv("JOB_WORKERS", &JOB_WORKERS)
+	c.loadEnv("JOB_POLL_INTERVAL", &JOB_POLL_INTERVAL)
+	c.loadEnv("JOB_MAX_ATTEMPTS", &JOB_MAX_ATTEMPTS)
+	c.loadEnv("JOB_RETRY_INTERVAL", &JOB_RETRY_INTERVAL)
+	c.loadEnv("JOB_LOCK_TIMEOUT", &JOB_LOCK_TIMEOUT)
+	c.loadEnv("JOB_RETENTION", &JOB_RETENTION)
 
-	grest.LoadEnv("APP_ENV", &APP_ENV)
-	grest.LoadEnv("APP_PORT", &APP_PORT)
-	grest.LoadEnv("APP_URL", &APP_URL)
-
-	grest.LoadEnv("IS_MAIN_SERVER", &IS_MAIN_SERVER)
-
-	grest.LoadEnv("ENV_FILE", &ENV_FILE)
-	grest.LoadEnv("IS_USE_MOCK_SERVICE", &IS_USE_MOCK_SERVICE)
-	grest.LoadEnv("IS_USE_MOCK_DB", &IS_USE_MOCK_DB)
-
-	grest.LoadEnv("LOG_LEVEL", &LOG_LEVEL)
-	grest.LoadEnv("LOG_CONSOLE_ENABLED", &LOG_CONSOLE_ENABLED)
-	grest.LoadEnv("LOG_CONSOLE_WITH_JSON", &LOG_CONSOLE_WITH_JSON)
-	grest.LoadEnv("LOG_CONSOLE_TIME_FORMAT", &LOG_CONSOLE_TIME_FORMAT)
-	grest.LoadEnv("LOG_CONSOLE_EXCLUDED_KEYS", &LOG_CONSOLE_EXCLUDED_KEYS)
-	grest.LoadEnv("LOG_FILE_ENABLED", &LOG_FILE_ENABLED)
-	grest.LoadEnv("LOG_FILE_WITH_JSON", &LOG_FILE_WITH_JSON)
-	grest.LoadEnv("LOG_FILE_USE_LOCAL_TIME", &LOG_FILE_USE_LOCAL_TIME)
-	grest.LoadEnv("LOG_FILE_FILENAME", &LOG_FILE_FILENAME)
-	grest.LoadEnv("LOG_FILE_MAX_SIZE", &LOG_FILE_MAX_SIZE)
-	grest.LoadEnv("LOG_FILE_MAX_AGE", &LOG_FILE_MAX_AGE)
-	grest.LoadEnv("LOG_FILE_MAX_BACKUPS", &LOG_FILE_MAX_BACKUPS)
-	grest.LoadEnv("LOG_WITH_DURATION", &LOG_WITH_DURATION)
-	grest.LoadEnv("LOG_WITH_REQUEST_HEADER", &LOG_WITH_REQUEST_HEADER)
-	grest.LoadEnv("LOG_WITH_REQUEST_BODY", &LOG_WITH_REQUEST_BODY)
-	grest.LoadEnv("LOG_WITH_RESPONSE_BODY", &LOG_WITH_RESPONSE_BODY)
-
-	grest.LoadEnv("JWT_KEY", &JWT_KEY)
-	grest.LoadEnv("CRYPTO_KEY", &CRYPTO_KEY)
-	grest.LoadEnv("CRYPTO_SALT", &CRYPTO_SALT)
-	grest.LoadEnv("CRYPTO_INFO", &CRYPTO_INFO)
-
-	grest.LoadEnv("DB_DRIVER", &DB_DRIVER)
-	grest.LoadEnv("DB_HOST", &DB_HOST)
-	grest.LoadEnv("DB_HOST_READ", &DB_HOST_READ)
-	grest.LoadEnv("DB_PORT", &DB_PORT)
-	grest.LoadEnv("DB_DATABASE", &DB_DATABASE)
-	grest.LoadEnv("DB_USERNAME", &DB_USERNAME)
-	grest.LoadEnv("DB_PASSWORD", &DB_PASSWORD)
-	grest.LoadEnv("DB_MAX_OPEN_CONNS", &DB_MAX_OPEN_CONNS)
-	grest.LoadEnv("DB_MAX_IDLE_CONNS", &DB_MAX_IDLE_CONNS)
-	grest.LoadEnv("DB_CONN_MAX_LIFETIME", &DB_CONN_MAX_LIFETIME)
-	grest.LoadEnv("DB_IS_DEBUG", &DB_IS_DEBUG)
-
-	grest.LoadEnv("REDIS_HOST", &REDIS_HOST)
-	grest.LoadEnv("REDIS_PORT", &REDIS_PORT)
-	grest.LoadEnv("REDIS_CACHE_DB", &REDIS_CACHE_DB)
-	grest.LoadEnv("REDIS_REPORT_DB", &REDIS_REPORT_DB)
-	grest.LoadEnv("REDIS_USERNAME", &REDIS_USERNAME)
-	grest.LoadEnv("REDIS_PASSWORD", &REDIS_PASSWORD)
-
-	grest.LoadEnv("FS_END_POINT", &FS_END_POINT)
-	grest.LoadEnv("FS_PORT", &FS_PORT)
-	grest.LoadEnv("FS_REGION", &FS_REGION)
-	grest.LoadEnv("FS_BUCKET_NAME", &FS_BUCKET_NAME)
-	grest.LoadEnv("FS_ACCESS_KEY", &FS_ACCESS_KEY)
-	grest.LoadEnv("FS_SECRET_KEY", &FS_SECRET_KEY)
-
-	grest.LoadEnv("TELEGRAM_ALERT_TOKEN", &TELEGRAM_ALERT_TOKEN)
-	grest.LoadEnv("TELEGRAM_ALERT_USER_ID", &TELEGRAM_ALERT_USER_ID)
+	c.loadEnv("WEBHOOK_MAX_ATTEMPTS", &WEBHOOK_MAX_ATTEMPTS)
+	c.loadEnv("WEBHOOK_TIMEOUT", &WEBHOOK_TIMEOUT)
 }
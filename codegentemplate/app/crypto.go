@@ -1,6 +1,9 @@
 package app
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 	"time"
 
@@ -25,17 +28,96 @@ var crpto *cryptoUtil
 
 // cryptoUtil represents a crypto utility.
 // It embeds grest.Crypto, indicating that cryptoUtil inherits from grest.Crypto.
+// The ciphertext is prefixed with the key id (Prefix + KeyID + ":"), so the key can be rotated
+// while the ciphertext of the retired keys can still be decrypted.
 type cryptoUtil struct {
 	grest.Crypto
+	KeyID       string            // the id of the current Key, empty to encrypt without the key id
+	Prefix      string            // the prefix of the ciphertext before the key id
+	RetiredKeys map[string]string // the retired keys by id, only used to decrypt
 }
 
 // configure configures the crypto utility instance.
 // It sets the encryption key (c.Key), salt (c.Salt), info (c.Info), and JWT key (c.JWTKey) to the corresponding environment variables.
+// It also sets the key id (c.KeyID), the ciphertext prefix (c.Prefix) and the retired keys (c.RetiredKeys) from CRYPTO_RETIRED_KEYS with "id:key" format separated by comma.
 func (c *cryptoUtil) configure() {
 	c.Key = CRYPTO_KEY
 	c.Salt = CRYPTO_SALT
 	c.Info = CRYPTO_INFO
 	c.JWTKey = JWT_KEY
+	c.KeyID = CRYPTO_KEY_ID
+	c.Prefix = CRYPTO_PREFIX
+	c.RetiredKeys = map[string]string{}
+	for _, k := range strings.Split(CRYPTO_RETIRED_KEYS, ",") {
+		if id, key, ok := strings.Cut(strings.TrimSpace(k), ":"); ok && id != "" && key != "" {
+			c.RetiredKeys[id] = key
+		}
+	}
+}
+
+// Encrypt encrypts the text with the current key and prefixes the ciphertext with the key id.
+func (c *cryptoUtil) Encrypt(text string) (string, error) {
+	ciphertext, err := c.Crypto.Encrypt(text)
+	if err != nil || c.KeyID == "" {
+		return ciphertext, err
+	}
+	return c.Prefix + c.KeyID + ":" + ciphertext, nil
+}
+
+// Decrypt decrypts the ciphertext with the key which id is prefixed to the ciphertext.
+// The ciphertext without the key id (encrypted before the key id is set) is decrypted with the current key, then with the retired keys.
+func (c *cryptoUtil) Decrypt(text string) (string, error) {
+	if id, ciphertext, ok := c.cut(text); ok {
+		if id == c.KeyID {
+			return c.Crypto.Decrypt(ciphertext)
+		}
+		return c.decrypt(c.RetiredKeys[id], ciphertext)
+	}
+	plain, err := c.Crypto.Decrypt(text)
+	if err == nil {
+		return plain, nil
+	}
+	for _, key := range c.RetiredKeys {
+		if p, e := c.decrypt(key, text); e == nil {
+			return p, nil
+		}
+	}
+	return "", err
+}
+
+// IsCurrentKey returns true if the ciphertext is encrypted with the current key, so it doesn't need to be rotated.
+// If the current key has no id, the ciphertext without the key id is encrypted with the current key if it can be decrypted with it,
+// since it may be encrypted with the retired key before the key id is used.
+func (c *cryptoUtil) IsCurrentKey(text string) bool {
+	id, _, ok := c.cut(text)
+	if c.KeyID == "" {
+		if ok {
+			return false
+		}
+		_, err := c.Crypto.Decrypt(text)
+		return err == nil
+	}
+	return ok && id == c.KeyID
+}
+
+// cut returns the key id and the ciphertext, it returns false if the text is not prefixed with the known key id.
+func (c *cryptoUtil) cut(text string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(text, c.Prefix)
+	if !ok {
+		return "", "", false
+	}
+	id, ciphertext, ok := strings.Cut(rest, ":")
+	if !ok || id == "" || (id != c.KeyID && c.RetiredKeys[id] == "") {
+		return "", "", false
+	}
+	return id, ciphertext, true
+}
+
+// decrypt decrypts the ciphertext with the key.
+func (c *cryptoUtil) decrypt(key, ciphertext string) (string, error) {
+	g := c.Crypto
+	g.Key = key
+	return g.Decrypt(ciphertext)
 }
 
 // NewToken generates a new token using UUID.
@@ -44,14 +126,29 @@ func (c *cryptoUtil) NewToken() string {
 	return strings.ReplaceAll(uuid.NewString(), "-", "")
 }
 
+// HMAC returns the hex encoded HMAC-SHA256 of the message using the key, it used to sign the webhook payload, etc.
+func (c *cryptoUtil) HMAC(key, message string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(message))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// BlindIndex returns the blind index of the value, it is the HMAC of the value with CRYPTO_BLIND_INDEX_KEY,
+// so the encrypted value can be filtered by equality without decrypting it.
+func (c *cryptoUtil) BlindIndex(val string) string {
+	return c.HMAC(CRYPTO_BLIND_INDEX_KEY, val)
+}
+
 // NewCrypto creates a new cryptoUtil instance with custom keys.
 // It initializes the instance, configures it, and assigns the custom keys (if provided) to the corresponding fields (c.Key, c.Salt, c.Info, c.JWTKey).
+// The ciphertext of the custom key is not prefixed with the key id.
 // It returns the created cryptoUtil instance.
 func NewCrypto(keys ...string) *cryptoUtil {
 	c := &cryptoUtil{}
 	c.configure()
 	if len(keys) > 0 {
 		c.Key = keys[0]
+		c.KeyID, c.RetiredKeys = "", map[string]string{}
 	}
 	if len(keys) > 1 {
 		c.Salt = keys[1]
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// registeredTable is the table which is registered to the db connection.
+type registeredTable struct {
+	ConnName string
+	Model    any
+}
+
+// RegisterTable registers the table to the db connection, it is also recorded so the encrypted columns can be rotated.
+func (d *dbUtil) RegisterTable(connName string, t any) error {
+	d.tables = append(d.tables, registeredTable{ConnName: connName, Model: t})
+	return d.DB.RegisterTable(connName, t)
+}
+
+// Rotate re-encrypts the NullEncryptedString columns of the registered tables with the current key (CRYPTO_KEY with CRYPTO_KEY_ID)
+// in batches of CRYPTO_ROTATE_BATCH_SIZE rows. The value which is already encrypted with the current key is skipped,
+// so it is safe to run it again after it is interrupted. It returns the number of updated rows.
+// The row which is failed to decrypt is logged and skipped, and the error with the number of the failed rows is returned at the end.
+//
+// To rotate the key, move the current key to CRYPTO_RETIRED_KEYS, set the new CRYPTO_KEY and CRYPTO_KEY_ID,
+// then run `go run main.go crypto rotate`. The retired key can be removed after the rotation is done.
+func (c *cryptoUtil) Rotate() (int64, error) {
+	total, failed := int64(0), int64(0)
+	for _, t := range DB().tables {
+		count, failedCount, err := c.rotateTable(t.ConnName, t.Model)
+		total += count
+		failed += failedCount
+		if err != nil {
+			return total, err
+		}
+	}
+	if failed > 0 {
+		return total, fmt.Errorf("%d rows are failed to rotate, see the warning log for the detail", failed)
+	}
+	return total, nil
+}
+
+// rotateTable re-encrypts the NullEncryptedString columns of the table, the rows are paged by the primary key.
+// The row is only updated if its encrypted columns are not changed since it is read, so the concurrent write is never reverted.
+// It returns the number of the updated rows and the rows which are failed to decrypt.
+func (c *cryptoUtil) rotateTable(connName string, model any) (int64, int64, error) {
+	tx, err := DB().Conn(connName)
+	if err != nil {
+		return 0, 0, err
+	}
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return 0, 0, err
+	}
+	columns := []string{}
+	for _, f := range stmt.Schema.Fields {
+		if f.FieldType == reflect.TypeOf(NullEncryptedString{}) && f.DBName != "" {
+			columns = append(columns, f.DBName)
+		}
+	}
+	if len(columns) == 0 {
+		return 0, 0, nil
+	}
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return 0, 0, fmt.Errorf("failed to rotate the encrypted columns of %s: the table has no primary key", stmt.Schema.Table)
+	}
+	table, pk := stmt.Schema.Table, stmt.Schema.PrioritizedPrimaryField.DBName
+	batchSize := CRYPTO_ROTATE_BATCH_SIZE
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	count, failed := int64(0), int64(0)
+	var lastID any
+	for {
+		rows := []map[string]any{}
+		q := tx.Table(table).Select(append([]string{pk}, columns...)).Order(pk).Limit(batchSize)
+		if lastID != nil {
+			q = q.Where(pk+" > ?", lastID)
+		}
+		if err := q.Find(&rows).Error; err != nil {
+			return count, failed, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			for _, row := range rows {
+				lastID = row[pk]
+				values, err := c.rotateRow(row, columns)
+				if err != nil {
+					Logger().Warn("Failed to rotate the encrypted columns",
+						slog.String("table", table),
+						slog.Any("id", row[pk]),
+						slog.Any("err", err),
+					)
+					failed++
+					continue
+				}
+				if len(values) == 0 {
+					continue
+				}
+				q := tx.Table(table).Where(pk+" = ?", row[pk])
+				for col := range values {
+					q = q.Where(col+" = ?", row[col])
+				}
+				res := q.Updates(values)
+				if res.Error != nil {
+					return res.Error
+				}
+				count += res.RowsAffected // 0 if the row is changed concurrently, it is already written with the current key
+			}
+			return nil
+		})
+		if err != nil {
+			return count, failed, err
+		}
+		if len(rows) < batchSize {
+			break
+		}
+	}
+	Logger().Info("Rotated the encrypted columns", slog.String("table", table), slog.Int64("rows", count), slog.Int64("failed", failed))
+	return count, failed, nil
+}
+
+// rotateRow returns the re-encrypted values of the columns which are not encrypted with the current key.
+func (c *cryptoUtil) rotateRow(row map[string]any, columns []string) (map[string]any, error) {
+	values := map[string]any{}
+	for _, col := range columns {
+		s := ""
+		switch v := row[col].(type) {
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		}
+		if s == "" || c.IsCurrentKey(s) {
+			continue
+		}
+		plain, err := c.Decrypt(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", col, err)
+		}
+		ciphertext, err := c.Encrypt(plain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", col, err)
+		}
+		values[col] = ciphertext
+	}
+	return values, nil
+}
@@ -18,3 +18,49 @@ func TestEncryptDecrypt(t *testing.T) {
 		t.Errorf("Expected decrypted [%v], got [%v]", plaintext, decrypted)
 	}
 }
+
+func TestEncryptDecryptRetiredKey(t *testing.T) {
+	old := NewCrypto("wAGyTpFQX5uKV3JInABXXEdpgFkQLPTf")
+	old.KeyID = "1"
+	c := NewCrypto("Zk3nQ8vR2mX7pL4tY9wB6cJ1hD5sF0gA")
+	c.KeyID, c.RetiredKeys = "2", map[string]string{"1": old.Key}
+
+	plaintext := "f4cac8b77a8d4cb5881fac72388bb226"
+	encrypted, err := old.Encrypt(plaintext)
+	if err != nil {
+		t.Errorf("Error occurred [%v]", err)
+	}
+	if c.IsCurrentKey(encrypted) {
+		t.Errorf("Expected [%v] is not encrypted with the current key", encrypted)
+	}
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil {
+		t.Errorf("Error occurred [%v]", err)
+	}
+	if decrypted != plaintext {
+		t.Errorf("Expected decrypted [%v], got [%v]", plaintext, decrypted)
+	}
+	rotated, err := c.Encrypt(decrypted)
+	if err != nil {
+		t.Errorf("Error occurred [%v]", err)
+	}
+	if !c.IsCurrentKey(rotated) {
+		t.Errorf("Expected [%v] is encrypted with the current key", rotated)
+	}
+}
+
+func TestIsCurrentKeyWithoutKeyID(t *testing.T) {
+	c := NewCrypto("Zk3nQ8vR2mX7pL4tY9wB6cJ1hD5sF0gA")
+	c.KeyID, c.RetiredKeys = "", map[string]string{"1": "wAGyTpFQX5uKV3JInABXXEdpgFkQLPTf"}
+
+	encrypted, err := c.Encrypt("f4cac8b77a8d4cb5881fac72388bb226")
+	if err != nil {
+		t.Errorf("Error occurred [%v]", err)
+	}
+	if !c.IsCurrentKey(encrypted) {
+		t.Errorf("Expected [%v] without the key id is encrypted with the current key", encrypted)
+	}
+	if c.IsCurrentKey(c.Prefix + "1:" + encrypted) {
+		t.Errorf("Expected the ciphertext of the retired key is not encrypted with the current key")
+	}
+}
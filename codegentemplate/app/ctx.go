@@ -2,9 +2,8 @@ package app
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
-	"reflect"
-	"time"
 
 	"gorm.io/gorm"
 	"grest.dev/grest"
@@ -15,16 +14,22 @@ const CtxKey = "ctx"
 type Ctx struct {
 	Lang   string // language code
 	Action Action // general request info
+	UserID string // authenticated user id, set it on your auth middleware
 	Err    error
 
-	IsAsync bool     // for async use, autocommit
-	mainTx  *gorm.DB // for normal use, commit & rollback from middleware
+	IsNoCache bool // set from the Cache-Control: no-cache request header, the cached data is ignored and reloaded
+
+	IsAsync     bool      // for async use, autocommit
+	mainTx      *gorm.DB  // for normal use, commit & rollback from middleware
+	afterCommit *[]func() // callbacks to run after mainTx is committed, shared between copies of Ctx
+	isBulk      bool      // set by RunBulk, the etag of each item is not validated
 }
 
 type Action struct {
-	Method string
-	Path   string
-	DataID string
+	Method  string
+	Path    string
+	DataID  string
+	IfMatch string // the If-Match request header, used for optimistic concurrency
 }
 
 // TxBegin begins a new transaction using the main database connection.
@@ -35,19 +40,31 @@ func (c *Ctx) TxBegin() error {
 		return err
 	}
 	c.mainTx = mainTx.Begin()
+	c.afterCommit = &[]func(){}
 	return nil
 }
 
 // TxCommit commits the current transaction if it exists (mainTx is not nil).
 // Called in middleware when there is no error (http status code is 2xx).
-// It does nothing if there is no active transaction.
-func (c *Ctx) TxCommit() {
+// After a successful commit, it runs the callbacks registered with AfterCommit on the background.
+// It returns the error of the commit, and does nothing if there is no active transaction.
+func (c *Ctx) TxCommit() error {
+	var err error
 	if c.mainTx != nil {
-		c.mainTx.Commit()
+		err = c.mainTx.Commit().Error
+		if err != nil {
+			Logger().Error("Failed to commit transaction", Logger().Attrs(*c, []any{slog.Any("err", err)})...)
+		} else if c.afterCommit != nil {
+			for _, fn := range *c.afterCommit {
+				Server().Go(*c, "Failed to run after commit callback", fn)
+			}
+		}
 	}
 
 	// reset to nil to use gorm autocommit if use goroutine, etc
 	c.mainTx = nil
+	c.afterCommit = nil
+	return err
 }
 
 // TxRollback rolls back the current transaction if it exists (mainTx is not nil).
@@ -59,6 +76,44 @@ func (c *Ctx) TxRollback() {
 	}
 	// reset to nil to use gorm autocommit if use goroutine, etc
 	c.mainTx = nil
+
+	// discard the callbacks, the data is never committed
+	c.afterCommit = nil
+}
+
+// AfterCommit registers fn to be called on the background after the current transaction is committed successfully.
+// The callbacks are discarded if the transaction is rolled back.
+// If there is no active transaction (autocommit), fn is called on the background immediately.
+// It is used by the redis job store to push the job after commit, the job store in the db doesn't need it since
+// the job is saved in the same transaction. The use case should use EnqueueHook or Enqueue instead, so the side effect
+// is retried by the job worker, fn is lost if the process is stopped before it is called.
+func (c Ctx) AfterCommit(fn func()) {
+	if !c.isInTx() {
+		Server().Go(c, "Failed to run after commit callback", fn)
+		return
+	}
+	*c.afterCommit = append(*c.afterCommit, fn)
+}
+
+// isInTx returns true if the ctx has the active transaction which is committed by the middleware.
+func (c Ctx) isInTx() bool {
+	return !c.IsAsync && c.mainTx != nil && c.afterCommit != nil
+}
+
+// afterCommitMark returns the number of the registered after commit callbacks, it is taken on the savepoint
+// so the callbacks registered after it can be discarded with discardAfterCommit when the savepoint is rolled back.
+func (c Ctx) afterCommitMark() int {
+	if c.afterCommit == nil {
+		return 0
+	}
+	return len(*c.afterCommit)
+}
+
+// discardAfterCommit discards the after commit callbacks registered after the mark of afterCommitMark.
+func (c Ctx) discardAfterCommit(mark int) {
+	if c.afterCommit != nil && mark < len(*c.afterCommit) {
+		*c.afterCommit = (*c.afterCommit)[:mark]
+	}
 }
 
 // Trans translates a given key using the language specified in the context (c.Lang).
@@ -127,44 +182,74 @@ func (c Ctx) NotFoundError(err error, entity, key, value string) error {
 	})
 }
 
-// This method performs a hook operation, which involves sleeping for 2 seconds and performing some data manipulation based on the provided parameters.
-// It checks if the old value implements the IsFlat() method and determines whether the data is flat.
-// If the data is not flat, it converts the old value to a structured format.
-// You can do anything you want with this method, for example to save user activity log, send callback/webhook, etc.
-func (c Ctx) Hook(method, reason, id string, old any) {
-
-	// kasih jeda 2 detik untuk memastikan db transaction nya sudah di commit
-	time.Sleep(2 * time.Second)
-
-	isFlat := false
-	flat, ok := old.(interface{ IsFlat() bool })
-	if ok {
-		isFlat = flat.IsFlat()
-	}
-
-	oldData := old
-	if !isFlat {
-		oldData = grest.NewJSON(old).ToStructured().Data
-	}
-	oldJSON, _ := json.MarshalIndent(oldData, "", "  ")
-	newJSON := []byte{}
-
-	model := reflect.ValueOf(old)
-	if m := model.MethodByName("Async"); m.IsValid() {
-		useCase := m.Call([]reflect.Value{reflect.ValueOf(c)})
-		if len(useCase) > 0 {
-			if u := useCase[0].MethodByName("GetByID"); u.IsValid() {
-				val := u.Call([]reflect.Value{reflect.ValueOf(id)})
-				if len(val) > 0 {
-					new := val[0].Interface()
-					if !isFlat {
-						new = grest.NewJSON(new).ToStructured().Data
-					}
-					newJSON, _ = json.MarshalIndent(new, "", "  ")
-				}
-			}
+// HookPayload is the payload of the hook job enqueued by EnqueueHook.
+// The old and the new value are captured when the data is written, so the hook doesn't see the later change.
+type HookPayload struct {
+	Entity string          `json:"entity"`
+	Method string          `json:"method"`
+	Reason string          `json:"reason"`
+	ID     string          `json:"id"`
+	Old    json.RawMessage `json:"old,omitempty"`
+	New    json.RawMessage `json:"new,omitempty"`
+}
+
+// EnqueueHook enqueues the hook job of the entity ("{entity}.hook") using the db transaction of the ctx,
+// so the hook is only run if the business data is committed, and it is retried if failed.
+// The old value is nil on create and the new value is nil on delete.
+// The job handler is registered on src/worker.go, it calls Hook with the payload.
+func (c Ctx) EnqueueHook(entity, method, reason, id string, old, new any) error {
+	p := HookPayload{Entity: entity, Method: method, Reason: reason, ID: id}
+	var err error
+	if old != nil {
+		p.Old, err = hookJSON(old)
+	}
+	if err == nil && new != nil {
+		p.New, err = hookJSON(new)
+	}
+	if err != nil {
+		return Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return Job().Enqueue(c, entity+".hook", p)
+}
+
+// hookJSON returns the json of the value for the hook, the value is converted to the structured format unless it is flat (IsFlat() is true).
+func hookJSON(v any) (json.RawMessage, error) {
+	if flat, ok := v.(interface{ IsFlat() bool }); !ok || !flat.IsFlat() {
+		v = grest.NewJSON(v).ToStructured().Data
+	}
+	return json.Marshal(v)
+}
+
+// This method performs a hook operation, which involves performing some data manipulation based on the provided payload.
+// It is called by the hook job enqueued with EnqueueHook after the db transaction is committed.
+// It saves the changes between the old and the new value to the activity log and sends the webhook to the subscribers
+// in one db transaction, so the hook job can be retried on error without saving the activity log twice.
+// You can do anything else you want with this method, for example to send notification, etc.
+func (c Ctx) Hook(p HookPayload) error {
+	isOwnTx := false
+	if !IS_USE_MOCK_DB && !c.isInTx() {
+		c.IsAsync = false
+		if err := c.TxBegin(); err != nil {
+			return err
 		}
+		isOwnTx = true
+	}
+
+	err := ActivityLog{}.Save(c, p.Entity, p.Method, p.Reason, p.ID, p.Old, p.New)
+	if err == nil {
+		data := p.New
+		if len(data) == 0 {
+			data = p.Old
+		}
+		err = Webhook().Dispatch(c, Webhook().Event(p.Entity, p.Method), data)
+	}
+
+	if !isOwnTx {
+		return err
+	}
+	if err != nil {
+		c.TxRollback()
+		return err
 	}
-	_ = oldJSON
-	_ = newJSON
+	return c.TxCommit()
 }
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"time"
 
 	"github.com/google/uuid"
@@ -145,3 +147,49 @@ func NewNullUUID(val ...string) NullUUID {
 type NullJSON struct {
 	grest.NullJSON
 }
+
+// NullEncryptedString is nullable String which is encrypted at rest, it embeds a NullString, so it is a plain string on the json and the open api documentation.
+// The value is encrypted with Crypto().Encrypt when it is saved to the db and decrypted with Crypto().Decrypt when it is scanned or queried with Query().Find.
+//
+// The encrypted value can't be filtered, add the blindindex tag with the column name of the blind index (Crypto().BlindIndex) to filter by equality,
+// the blind index is set automatically on create and update, for example :
+//
+//	TaxID      app.NullEncryptedString `json:"tax_id" db:"m.tax_id" gorm:"column:tax_id" blindindex:"tax_id_bidx"`
+//	TaxIDBIdx  app.NullString          `json:"-"      db:"-"        gorm:"column:tax_id_bidx;index"`
+type NullEncryptedString struct {
+	NullString
+}
+
+// NewNullEncryptedString return NullEncryptedString
+func NewNullEncryptedString(val string) NullEncryptedString {
+	n := NullEncryptedString{}
+	n.Valid = true
+	n.String = val
+	return n
+}
+
+// Value implements the driver.Valuer interface, it returns the encrypted value.
+func (n NullEncryptedString) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return Crypto().Encrypt(n.String)
+}
+
+// Scan implements the sql.Scanner interface, it decrypts the scanned value.
+func (n *NullEncryptedString) Scan(value any) error {
+	s := sql.NullString{}
+	if err := s.Scan(value); err != nil {
+		return err
+	}
+	n.Valid, n.String = s.Valid, ""
+	if !s.Valid {
+		return nil
+	}
+	plain, err := Crypto().Decrypt(s.String)
+	if err != nil {
+		return err
+	}
+	n.String = plain
+	return nil
+}
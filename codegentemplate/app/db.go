@@ -33,6 +33,7 @@ var db *dbUtil
 // It embeds grest.DB, indicating that dbUtil inherits from grest.DB.
 type dbUtil struct {
 	grest.DB
+	tables []registeredTable // the registered tables, used by the cli command such as `go run main.go crypto rotate`
 }
 
 // configure configures the db utility instance.
@@ -80,6 +81,10 @@ func (d *dbUtil) Connect(connName string, c grest.DBConfig) error {
 		return err
 	}
 
+	if err = registerBlindIndexCallback(gormDB); err != nil {
+		return err
+	}
+
 	if DB_IS_DEBUG {
 		gormDB = gormDB.Debug()
 	}
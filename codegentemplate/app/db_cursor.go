@@ -0,0 +1,256 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"grest.dev/grest"
+)
+
+// These are the query params of the cursor (keyset) pagination.
+const (
+	QueryCursor      = "$cursor"        // the opaque cursor from the next or previous link
+	QueryCursorLimit = "$limit"         // the number of items to retrieve, default = 10
+	QuerySkipCount   = "$is_skip_count" // skip the total count, for both page and cursor pagination
+)
+
+// cursorSort is the sort of the cursor pagination.
+type cursorSort struct {
+	Key               string // the json key on the query result
+	Column            string // the db column expression
+	IsDesc            bool
+	IsCaseInsensitive bool
+}
+
+// cursorData is the decoded value of the cursor.
+type cursorData struct {
+	Values     []any `json:"v"`
+	IsPrevious bool  `json:"p,omitempty"`
+}
+
+// IsCursorPagination returns true if the list is requested with the cursor pagination ($cursor or $limit),
+// otherwise the page pagination ($page & $per_page) is used.
+func (queryUtil) IsCursorPagination(query url.Values) bool {
+	return query.Has(QueryCursor) || query.Has(QueryCursorLimit)
+}
+
+// FindByCursor gets the data from database based on model and query with the cursor (keyset) pagination.
+// The data is sorted by the $sort query param (or the default sort of the model) plus the id, and the cursor holds
+// the sort values of the first or the last item, so the next page is queried with the where condition instead of the offset.
+// The sort fields should not be nullable, since the null value can't be compared.
+// newModel is called for each query, since the model collects the filters and sorts when it is queried.
+func (q queryUtil) FindByCursor(db *gorm.DB, newModel func() ModelInterface, query url.Values) (ListModel, error) {
+	res := ListModel{}
+	limit := 10
+	if l, err := strconv.Atoi(query.Get(QueryCursorLimit)); err == nil && l > 0 {
+		limit = l
+	}
+	res.PageContext.PerPage = limit
+
+	sorts := q.cursorSorts(newModel(), query.Get(grest.QuerySort))
+	cursor := cursorData{}
+	if c := query.Get(QueryCursor); c != "" {
+		b, err := base64.RawURLEncoding.DecodeString(c)
+		if err == nil {
+			err = json.Unmarshal(b, &cursor)
+		}
+		if err != nil || len(cursor.Values) != len(sorts) {
+			return res, Error().New(http.StatusBadRequest, "invalid "+QueryCursor)
+		}
+	}
+
+	// set the total count
+	fq := cloneQuery(query)
+	fq.Del(QueryCursor)
+	fq.Del(QueryCursorLimit)
+	fq.Del(grest.QueryPage)
+	fq.Del(grest.QueryDisablePagination)
+	if query.Get(QuerySkipCount) != "true" {
+		fq.Set(grest.QueryLimit, strconv.Itoa(limit))
+		count, _, _, pageCount, err := q.PaginationInfo(db, newModel(), fq)
+		if err != nil {
+			return res, Error().New(http.StatusInternalServerError, err.Error())
+		}
+		res.Count, res.PageContext.PageCount = count, pageCount
+	}
+
+	// query one more item to know if there is more data
+	selects := fq.Get(grest.QuerySelect)
+	q.setCursorQuery(fq, sorts, cursor.IsPrevious)
+	fq.Set(grest.QueryLimit, strconv.Itoa(limit+1))
+	tx := db
+	if len(cursor.Values) > 0 {
+		where, args := q.cursorWhere(sorts, cursor)
+		tx = db.Where(where, args...)
+	}
+	data, err := q.Find(tx, newModel(), fq)
+	if err != nil {
+		return res, Error().New(http.StatusInternalServerError, err.Error())
+	}
+	hasMore := len(data) > limit
+	if hasMore {
+		data = data[:limit]
+	}
+	if cursor.IsPrevious {
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	// set the cursor of the first and the last item
+	if len(data) > 0 {
+		if hasMore || cursor.IsPrevious {
+			res.PageContext.NextCursor = q.encodeCursor(sorts, data[len(data)-1], false)
+		}
+		if (hasMore && cursor.IsPrevious) || (!cursor.IsPrevious && len(cursor.Values) > 0) {
+			res.PageContext.PreviousCursor = q.encodeCursor(sorts, data[0], true)
+		}
+	}
+
+	// remove the sort fields which is not selected
+	if selects != "" {
+		selected := map[string]bool{}
+		for _, s := range strings.Split(selects, ",") {
+			selected[strings.TrimSpace(s)] = true
+		}
+		for _, row := range data {
+			for _, s := range sorts {
+				if !selected[s.Key] {
+					delete(row, s.Key)
+				}
+			}
+		}
+	}
+	res.SetData(data, query)
+	return res, nil
+}
+
+// cursorSorts returns the sorts of the cursor pagination from the $sort query param or the default sort of the model,
+// the id is added as the last sort to make the order unique.
+func (queryUtil) cursorSorts(model ModelInterface, sortQuery string) []cursorSort {
+	columns := map[string]string{}
+	keys := map[string]string{}
+	var find func(t reflect.Type)
+	find = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				find(f.Type)
+				continue
+			}
+			key, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			column := f.Tag.Get("db")
+			if key != "" && key != "-" && column != "" && column != "-" {
+				columns[key] = column
+				keys[column] = key
+			}
+		}
+	}
+	find(reflect.TypeOf(model))
+
+	sorts := []cursorSort{}
+	if sortQuery != "" {
+		for _, s := range strings.Split(sortQuery, ",") {
+			s = strings.TrimSpace(s)
+			sort := cursorSort{IsDesc: strings.HasPrefix(s, "-")}
+			sort.Key, sort.IsCaseInsensitive = strings.CutSuffix(strings.TrimPrefix(s, "-"), ":i")
+			if column, ok := columns[sort.Key]; ok {
+				sort.Column = column
+				if sort.IsCaseInsensitive {
+					sort.Column = "lower(" + column + ")"
+				}
+				sorts = append(sorts, sort)
+			}
+		}
+	} else if m, ok := model.(interface{ GetSorts() []map[string]any }); ok {
+		for _, s := range m.GetSorts() {
+			column, _ := s["column"].(string)
+			direction, _ := s["direction"].(string)
+			if key, ok := keys[column]; ok {
+				sorts = append(sorts, cursorSort{Key: key, Column: column, IsDesc: strings.ToLower(direction) == "desc"})
+			}
+		}
+	}
+	if column, ok := columns["id"]; ok {
+		isDesc := false
+		for _, s := range sorts {
+			if s.Key == "id" {
+				return sorts
+			}
+			isDesc = s.IsDesc
+		}
+		sorts = append(sorts, cursorSort{Key: "id", Column: column, IsDesc: isDesc})
+	}
+	return sorts
+}
+
+// setCursorQuery sets the $sort query param of the sorts (reversed for the previous page) and the first page,
+// and adds the sort fields to the $select query param so the cursor can be taken from the queried items.
+func (queryUtil) setCursorQuery(query url.Values, sorts []cursorSort, isPrevious bool) {
+	sortQuery := []string{}
+	for _, s := range sorts {
+		sort := s.Key
+		if s.IsDesc != isPrevious {
+			sort = "-" + sort
+		}
+		if s.IsCaseInsensitive {
+			sort += ":i"
+		}
+		sortQuery = append(sortQuery, sort)
+	}
+	query.Set(grest.QuerySort, strings.Join(sortQuery, ","))
+	query.Set(grest.QueryPage, "1")
+	if query.Get(grest.QuerySelect) != "" {
+		for _, s := range sorts {
+			query.Set(grest.QuerySelect, query.Get(grest.QuerySelect)+","+s.Key)
+		}
+	}
+}
+
+// cursorWhere returns the keyset condition to get the items after the cursor, for example with sort a asc, b desc :
+// (a > ?) OR (a = ? AND b < ?)
+func (queryUtil) cursorWhere(sorts []cursorSort, cursor cursorData) (string, []any) {
+	conds := []string{}
+	args := []any{}
+	for i, s := range sorts {
+		cond := []string{}
+		for j := 0; j < i; j++ {
+			cond = append(cond, sorts[j].Column+" = "+cursorPlaceholder(sorts[j]))
+			args = append(args, cursor.Values[j])
+		}
+		op := " > "
+		if s.IsDesc != cursor.IsPrevious {
+			op = " < "
+		}
+		cond = append(cond, s.Column+op+cursorPlaceholder(s))
+		args = append(args, cursor.Values[i])
+		conds = append(conds, "("+strings.Join(cond, " AND ")+")")
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+func cursorPlaceholder(s cursorSort) string {
+	if s.IsCaseInsensitive {
+		return "lower(?)"
+	}
+	return "?"
+}
+
+// encodeCursor returns the opaque cursor of the sort values of the item.
+func (queryUtil) encodeCursor(sorts []cursorSort, row map[string]any, isPrevious bool) string {
+	c := cursorData{Values: make([]any, len(sorts)), IsPrevious: isPrevious}
+	for i, s := range sorts {
+		c.Values[i] = row[s.Key]
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+type cursorTestModel struct {
+	Model
+	ID        NullUUID     `json:"id"         db:"m.id"`
+	Name      NullString   `json:"name"       db:"m.name"`
+	CreatedAt NullDateTime `json:"created_at" db:"m.created_at"`
+	Note      NullText     `json:"note"       db:"-"`
+}
+
+func TestCursorWhere(t *testing.T) {
+	sorts := Query().cursorSorts(&cursorTestModel{}, "-created_at,name:i,note")
+	if len(sorts) != 3 || sorts[2].Key != "id" || sorts[2].IsDesc || sorts[1].Column != "lower(m.name)" {
+		t.Fatalf("Expected sorts [-created_at name:i id], got %v", sorts)
+	}
+
+	cursor := cursorData{Values: []any{"2026-10-19", "a", "1"}}
+	expected := "((m.created_at < ?) OR (m.created_at = ? AND lower(m.name) > lower(?)) OR (m.created_at = ? AND lower(m.name) = lower(?) AND m.id > ?))"
+	where, args := Query().cursorWhere(sorts, cursor)
+	if where != expected || len(args) != 6 {
+		t.Errorf("Expected where [%v] with 6 args, got [%v] with %v args", expected, where, len(args))
+	}
+
+	cursor.IsPrevious = true
+	expected = "((m.created_at > ?) OR (m.created_at = ? AND lower(m.name) < lower(?)) OR (m.created_at = ? AND lower(m.name) = lower(?) AND m.id < ?))"
+	if where, _ = Query().cursorWhere(sorts, cursor); where != expected {
+		t.Errorf("Expected where [%v], got [%v]", expected, where)
+	}
+
+	c := Query().encodeCursor(sorts, map[string]any{"created_at": "2026-10-19", "name": "a", "id": "1"}, true)
+	b, err := base64.RawURLEncoding.DecodeString(c)
+	if err != nil {
+		t.Fatalf("Expected valid cursor, got [%v]", err)
+	}
+	if string(b) != `{"v":["2026-10-19","a","1"],"p":true}` {
+		t.Errorf("Expected cursor data, got [%v]", string(b))
+	}
+	decoded := cursorData{}
+	if err = json.Unmarshal(b, &decoded); err != nil || !decoded.IsPrevious || len(decoded.Values) != 3 {
+		t.Errorf("Expected decoded cursor, got [%v] [%v]", decoded, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 
 	"gorm.io/gorm"
@@ -44,6 +45,68 @@ func (queryUtil) BindJSON(body []byte, param ...any) error {
 	return nil
 }
 
+// JSONKeys returns the flat keys (for example "category.id") of the json request body, including the key with null value.
+// It is used to distinguish the absent field from the explicit null on the partial update.
+func (queryUtil) JSONKeys(body []byte) ([]string, error) {
+	data := map[string]any{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	var flatten func(prefix string, data map[string]any)
+	flatten = func(prefix string, data map[string]any) {
+		for k, v := range data {
+			if obj, ok := v.(map[string]any); ok && len(obj) > 0 {
+				flatten(prefix+k+".", obj)
+			} else {
+				keys = append(keys, prefix+k)
+			}
+		}
+	}
+	flatten("", data)
+	return keys, nil
+}
+
+// Columns returns the db columns of the model fields which json tag is in the keys.
+// The key can be the parent of the json tag, for example "category" (set to null) matches "category.id".
+func (queryUtil) Columns(model any, keys []string) []string {
+	columns := []string{}
+	var find func(t reflect.Type)
+	find = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				find(f.Type)
+				continue
+			}
+			column := ""
+			for _, tag := range strings.Split(f.Tag.Get("gorm"), ";") {
+				if c, ok := strings.CutPrefix(tag, "column:"); ok {
+					column = c
+				}
+			}
+			jsonKey, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if column == "" || jsonKey == "" || jsonKey == "-" {
+				continue
+			}
+			for _, k := range keys {
+				if k == jsonKey || strings.HasPrefix(jsonKey, k+".") {
+					columns = append(columns, column)
+					if bi := f.Tag.Get("blindindex"); bi != "" {
+						columns = append(columns, bi)
+					}
+					break
+				}
+			}
+		}
+	}
+	find(reflect.TypeOf(model))
+	return columns
+}
+
 // Parse parse url to url.Values.
 func (queryUtil) Parse(originalURL string) url.Values {
 	query := url.Values{}
@@ -89,15 +152,26 @@ func (q queryUtil) First(db *gorm.DB, model ModelInterface, query url.Values) er
 }
 
 // Find get paginated data from database based on model and query.
+// The encrypted fields are filtered by the blind index and decrypted.
 func (queryUtil) Find(db *gorm.DB, model ModelInterface, query url.Values) ([]map[string]any, error) {
+	fields := encryptedFields(model)
+	db, query = blindIndexFilter(db, fields, query)
 	q := &grest.DBQuery{}
 	q.DB = db
 	q.Schema = model.GetSchema()
 	q.Query = query
-	return q.Find(q.Schema, query)
+	data, err := q.Find(q.Schema, query)
+	if err != nil {
+		return data, err
+	}
+	if err = decryptFields(fields, data); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 // PaginationInfo get pagination info from database based on model and query.
+// The count and page count are 0 if the $is_skip_count query param is true, since the count is expensive on the large table.
 func (queryUtil) PaginationInfo(db *gorm.DB, model ModelInterface, query url.Values) (int64, int, int, int, error) {
 	var err error
 	count, page, perPage, pageCount := int64(0), 0, 0, 0
@@ -105,6 +179,7 @@ func (queryUtil) PaginationInfo(db *gorm.DB, model ModelInterface, query url.Val
 		return count, page, -1, pageCount, err
 	}
 
+	db, query = blindIndexFilter(db, encryptedFields(model), query)
 	q := &grest.DBQuery{}
 	q.DB = db
 	q.Schema = model.GetSchema()
@@ -113,6 +188,10 @@ func (queryUtil) PaginationInfo(db *gorm.DB, model ModelInterface, query url.Val
 	if err != nil {
 		return count, page, perPage, pageCount, err
 	}
+	if query.Get(QuerySkipCount) == "true" {
+		page, perPage = q.GetPageLimit()
+		return count, page, perPage, pageCount, err
+	}
 	err = tx.Count(&count).Error
 	if err != nil || query.Get(grest.QueryLimit) == "0" {
 		return count, page, perPage, pageCount, err
@@ -122,8 +201,16 @@ func (queryUtil) PaginationInfo(db *gorm.DB, model ModelInterface, query url.Val
 	return count, page, perPage, pageCount, err
 }
 
-// Return return any to structured data if not flat
-func (queryUtil) Return(res any, isFlat bool) any {
+// Return return any to structured data if not flat,
+// the list is returned as the results array only if it is requested with $envelope=false.
+func (q queryUtil) Return(res any, isFlat bool) any {
+	if list, ok := res.(ListModel); ok && list.isEnvelopeDisabled {
+		data := make([]any, len(list.Data))
+		for i, d := range list.Data {
+			data[i] = q.Return(d, isFlat)
+		}
+		return data
+	}
 	if isFlat {
 		return res
 	}
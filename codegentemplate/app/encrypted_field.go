@@ -0,0 +1,134 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// encryptedField is the NullEncryptedString field of the model.
+type encryptedField struct {
+	Key              string // the json key
+	Column           string // the db column expression, for example m.tax_id
+	BlindIndexColumn string // the db column expression of the blind index, empty if it has no blind index
+}
+
+// encryptedFields returns the NullEncryptedString fields of the model.
+func encryptedFields(model any) []encryptedField {
+	fields := []encryptedField{}
+	var find func(t reflect.Type)
+	find = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Type == reflect.TypeOf(NullEncryptedString{}) {
+				key, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+				column := f.Tag.Get("db")
+				if key == "" || key == "-" || column == "" || column == "-" {
+					continue
+				}
+				field := encryptedField{Key: key, Column: column}
+				if bi := f.Tag.Get("blindindex"); bi != "" {
+					field.BlindIndexColumn = bi
+					if alias, _, ok := strings.Cut(column, "."); ok {
+						field.BlindIndexColumn = alias + "." + bi
+					}
+				}
+				fields = append(fields, field)
+			} else if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				find(f.Type)
+			}
+		}
+	}
+	find(reflect.TypeOf(model))
+	return fields
+}
+
+// blindIndexFilter replaces the equality filter of the encrypted fields on the query with the where condition of the blind index,
+// for example ?tax_id=123 or ?tax_id.$eq=123 is queried with m.tax_id_bidx = Crypto().BlindIndex("123").
+func blindIndexFilter(db *gorm.DB, fields []encryptedField, query url.Values) (*gorm.DB, url.Values) {
+	isCloned := false
+	for _, f := range fields {
+		if f.BlindIndexColumn == "" {
+			continue
+		}
+		for _, key := range []string{f.Key, f.Key + ".$eq"} {
+			if !query.Has(key) {
+				continue
+			}
+			if !isCloned {
+				query, isCloned = cloneQuery(query), true
+			}
+			db = db.Where(f.BlindIndexColumn+" = ?", Crypto().BlindIndex(query.Get(key)))
+			query.Del(key)
+		}
+	}
+	return db, query
+}
+
+// decryptFields decrypts the encrypted fields of the queried data.
+// It returns error if the value can't be decrypted, like NullEncryptedString.Scan, so the ciphertext is never returned to the client.
+func decryptFields(fields []encryptedField, data []map[string]any) error {
+	for _, row := range data {
+		for _, f := range fields {
+			if s, ok := row[f.Key].(string); ok && s != "" {
+				plain, err := Crypto().Decrypt(s)
+				if err != nil {
+					return fmt.Errorf("failed to decrypt %s: %w", f.Key, err)
+				}
+				row[f.Key] = plain
+			}
+		}
+	}
+	return nil
+}
+
+// registerBlindIndexCallback registers the gorm callback to set the blind index column of the encrypted fields on create and update.
+func registerBlindIndexCallback(db *gorm.DB) error {
+	err := db.Callback().Create().Before("gorm:create").Register("app:blind_index", setBlindIndex)
+	if err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("app:blind_index", setBlindIndex)
+}
+
+// setBlindIndex sets the blind index field (by the blindindex tag) from the plain value of the NullEncryptedString field.
+func setBlindIndex(tx *gorm.DB) {
+	stmt := tx.Statement
+	if stmt.Schema == nil {
+		return
+	}
+	for _, f := range stmt.Schema.Fields {
+		column := f.Tag.Get("blindindex")
+		if column == "" {
+			continue
+		}
+		bf := stmt.Schema.LookUpField(column)
+		if bf == nil {
+			continue
+		}
+		set := func(rv reflect.Value) {
+			val, _ := f.ValueOf(stmt.Context, rv)
+			bi := NullString{}
+			if v, ok := val.(NullEncryptedString); ok && v.Valid {
+				bi = NewNullString(Crypto().BlindIndex(v.String))
+			}
+			if err := bf.Set(stmt.Context, rv, bi); err != nil {
+				tx.AddError(err)
+			}
+		}
+		switch stmt.ReflectValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < stmt.ReflectValue.Len(); i++ {
+				set(reflect.Indirect(stmt.ReflectValue.Index(i)))
+			}
+		case reflect.Struct:
+			set(stmt.ReflectValue)
+		}
+	}
+}
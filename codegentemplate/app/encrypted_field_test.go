@@ -0,0 +1,31 @@
+package app
+
+import (
+	"testing"
+)
+
+type encryptedTestModel struct {
+	Model
+	Name      NullString          `json:"name"   db:"m.name"   gorm:"column:name"`
+	TaxID     NullEncryptedString `json:"tax_id" db:"m.tax_id" gorm:"column:tax_id" blindindex:"tax_id_bidx"`
+	TaxIDBIdx NullString          `json:"-"      db:"-"        gorm:"column:tax_id_bidx;index"`
+	Account   NullEncryptedString `json:"account" db:"m.account" gorm:"column:account"`
+}
+
+func TestEncryptedFields(t *testing.T) {
+	fields := encryptedFields(&encryptedTestModel{})
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 encrypted fields, got %v", fields)
+	}
+	if fields[0].Key != "tax_id" || fields[0].Column != "m.tax_id" || fields[0].BlindIndexColumn != "m.tax_id_bidx" {
+		t.Errorf("Expected tax_id with blind index m.tax_id_bidx, got %v", fields[0])
+	}
+	if fields[1].Key != "account" || fields[1].BlindIndexColumn != "" {
+		t.Errorf("Expected account without blind index, got %v", fields[1])
+	}
+
+	columns := Query().Columns(&encryptedTestModel{}, []string{"tax_id"})
+	if len(columns) != 2 || columns[0] != "tax_id" || columns[1] != "tax_id_bidx" {
+		t.Errorf("Expected columns [tax_id tax_id_bidx], got %v", columns)
+	}
+}
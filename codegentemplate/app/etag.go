@@ -0,0 +1,86 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ETag returns the strong entity tag of the data based on its id and the last updated time,
+// it is changed every time the data is updated.
+// The updated time is json encoded, so the etag of the cached data is same as the data from the db.
+func ETag(id string, updatedAt NullDateTime) string {
+	t, _ := json.Marshal(updatedAt)
+	h := sha256.Sum256(append([]byte(id+"|"), t...))
+	return `"` + hex.EncodeToString(h[:8]) + `"`
+}
+
+// IsETagMatch reports whether the value of If-Match or If-None-Match header matches the etag.
+// The header can be "*" or the comma separated list of etags. With isWeakAllowed, the weak etag (W/"...") is compared by its value
+// (the weak comparison for If-None-Match), otherwise the weak etag never matches (the strong comparison for If-Match, RFC 7232 section 3.1).
+func IsETagMatch(header, etag string, isWeakAllowed bool) bool {
+	if !isWeakAllowed && strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	for _, h := range strings.Split(header, ",") {
+		h = strings.TrimSpace(h)
+		if h == "*" {
+			return true
+		}
+		if isWeakAllowed {
+			h = strings.TrimPrefix(h, "W/")
+			if h == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if h == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateETag validates the If-Match header of the request against the current etag of the data before it is changed.
+// It returns 412 if the data has been changed since the client read it, and 428 if IS_REQUIRE_IF_MATCH is true
+// and the If-Match header is empty. The bulk request and the async process are not validated.
+func (c Ctx) ValidateETag(etag string) error {
+	if c.IsAsync || c.isBulk {
+		return nil
+	}
+	if c.Action.IfMatch == "" {
+		if IS_REQUIRE_IF_MATCH {
+			return Error().New(http.StatusPreconditionRequired, c.Trans("428_precondition_required"))
+		}
+		return nil
+	}
+	if !IsETagMatch(c.Action.IfMatch, etag, false) {
+		return c.PreconditionFailedError()
+	}
+	return nil
+}
+
+// WhereNotModified adds the condition that the data is not modified since it is read with the updatedAt,
+// so the update or delete affects no row if the data is modified concurrently after ValidateETag.
+// The caller returns 412 when no row is affected, see PreconditionFailedError.
+func WhereNotModified(tx *gorm.DB, updatedAt NullDateTime) *gorm.DB {
+	if !updatedAt.Valid {
+		return tx.Where("updated_at is null")
+	}
+	return tx.Where("updated_at = ?", updatedAt)
+}
+
+// PreconditionFailedError returns the 412 error of the data which has been changed since the client read it.
+func (c Ctx) PreconditionFailedError() error {
+	return Error().New(http.StatusPreconditionFailed, c.Trans("412_precondition_failed"))
+}
+
+// OpenAPIETagHeader returns the open api document of the ETag response header.
+func OpenAPIETagHeader() map[string]any {
+	return map[string]any{
+		"description": "The version of the data, send it on If-Match header to update or delete the data",
+		"schema":      map[string]any{"type": "string"},
+	}
+}
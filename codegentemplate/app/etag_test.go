@@ -0,0 +1,51 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"grest.dev/grest"
+)
+
+func TestETag(t *testing.T) {
+	updatedAt := NewNullDateTime(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC))
+	etag := ETag("1", updatedAt)
+	if etag != ETag("1", updatedAt) {
+		t.Errorf("Expected same etag for same data")
+	}
+	if etag == ETag("1", NewNullDateTime(updatedAt.Time.Add(time.Second))) {
+		t.Errorf("Expected different etag for updated data")
+	}
+
+	// the expected match of the weak comparison (If-None-Match) and the strong comparison (If-Match)
+	for header, expected := range map[string][2]bool{
+		etag:               {true, true},
+		"W/" + etag:        {true, false},
+		`"other", ` + etag: {true, true},
+		"*":                {true, true},
+		`"other"`:          {false, false},
+		"":                 {false, false},
+	} {
+		if IsETagMatch(header, etag, true) != expected[0] {
+			t.Errorf("Expected weak IsETagMatch(%v) [%v], got [%v]", header, expected[0], !expected[0])
+		}
+		if IsETagMatch(header, etag, false) != expected[1] {
+			t.Errorf("Expected strong IsETagMatch(%v) [%v], got [%v]", header, expected[1], !expected[1])
+		}
+	}
+
+	c := Ctx{Action: Action{IfMatch: `"other"`}}
+	err := c.ValidateETag(etag)
+	if e, ok := err.(*grest.Error); !ok || e.StatusCode() != http.StatusPreconditionFailed {
+		t.Errorf("Expected error code [%v], got [%v]", http.StatusPreconditionFailed, err)
+	}
+	c.Action.IfMatch = "W/" + etag
+	if err = c.ValidateETag(etag); err == nil {
+		t.Errorf("Expected the weak etag doesn't match the If-Match")
+	}
+	c.Action.IfMatch = etag
+	if err = c.ValidateETag(etag); err != nil {
+		t.Errorf("Expected no error, got [%v]", err)
+	}
+}
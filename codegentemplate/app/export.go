@@ -0,0 +1,400 @@
+package app
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"grest.dev/grest"
+)
+
+// These are the supported format of the list export.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatXLSX   = "xlsx"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// These are the content type of the list export.
+const (
+	MIMECSV    = "text/csv"
+	MIMEXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MIMENDJSON = "application/x-ndjson"
+)
+
+// QueryFormat is the query param to export the list, it overrides the Accept header.
+const QueryFormat = "$format"
+
+// ExportFile is the exported file, it is used as the response of the export in the open api documentation.
+type ExportFile struct{}
+
+// OpenAPISchemaName returns the name of the ExportFile schema in the open api documentation.
+func (ExportFile) OpenAPISchemaName() string {
+	return "ExportFile"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ExportFile in the open api documentation.
+func (ExportFile) GetOpenAPISchema() map[string]any {
+	return map[string]any{"type": "string", "format": "binary"}
+}
+
+// ExportFormat returns the export format based on the $format query param or the Accept header,
+// it returns empty string if the list is not exported (json).
+func (queryUtil) ExportFormat(accept, format string) string {
+	switch strings.ToLower(format) {
+	case ExportFormatCSV, ExportFormatXLSX, ExportFormatNDJSON:
+		return strings.ToLower(format)
+	}
+	for _, a := range strings.Split(accept, ",") {
+		mime, _, _ := strings.Cut(strings.TrimSpace(a), ";")
+		switch mime {
+		case MIMECSV:
+			return ExportFormatCSV
+		case MIMEXLSX:
+			return ExportFormatXLSX
+		case MIMENDJSON:
+			return ExportFormatNDJSON
+		case "application/json", "*/*":
+			return ""
+		}
+	}
+	return ""
+}
+
+// ExportContentType returns the content type of the export format.
+func (queryUtil) ExportContentType(format string) string {
+	switch format {
+	case ExportFormatCSV:
+		return MIMECSV + "; charset=utf-8"
+	case ExportFormatXLSX:
+		return MIMEXLSX
+	}
+	return MIMENDJSON
+}
+
+// Export writes all data based on model and query to w with the export format.
+// It applies the same filters, sorts and $select as Find, but the data is queried in batches of EXPORT_BATCH_SIZE
+// and written as soon as the batch is loaded, so the whole table is never loaded to the memory.
+// The batches are paged with the keyset of the sorts plus the id like FindByCursor, so the deep batch is as fast as the first one
+// and the concurrent insert or delete never skips or duplicates the row.
+// The column headers of csv and xlsx are translated with the lang, and the ndjson rows follow the json response (structured if not flat).
+// newModel is called for each batch, since the model collects the filters and sorts when it is queried.
+func (q queryUtil) Export(w io.Writer, format, lang string, db *gorm.DB, newModel func() ModelInterface, query url.Values) error {
+	model := newModel()
+	query = cloneQuery(query)
+	query.Del(QueryFormat)
+	query.Del(grest.QueryDisablePagination)
+	query.Set(grest.QueryLimit, strconv.Itoa(EXPORT_BATCH_SIZE))
+
+	isFlat := false
+	if f, ok := model.(interface{ IsFlat() bool }); ok {
+		isFlat = f.IsFlat()
+	}
+	fields := q.ExportFields(model, query.Get(grest.QuerySelect))
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = Translator().Trans(lang, f)
+	}
+
+	var ew exportWriter
+	switch format {
+	case ExportFormatCSV:
+		ew = &csvExportWriter{w: csv.NewWriter(w)}
+	case ExportFormatXLSX:
+		ew = &xlsxExportWriter{zw: zip.NewWriter(w)}
+	default:
+		ew = &ndjsonExportWriter{w: bufio.NewWriter(w), isFlat: isFlat}
+	}
+	if err := ew.WriteHeader(header); err != nil {
+		return err
+	}
+	sorts := q.cursorSorts(model, query.Get(grest.QuerySort))
+	q.setCursorQuery(query, sorts, false)
+	cursor := cursorData{}
+	for {
+		tx := db
+		if len(cursor.Values) > 0 {
+			where, args := q.cursorWhere(sorts, cursor)
+			tx = db.Where(where, args...)
+		}
+		rows, err := q.Find(tx, newModel(), query)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err = ew.WriteRow(fields, row); err != nil {
+				return err
+			}
+		}
+		if len(rows) < EXPORT_BATCH_SIZE {
+			break
+		}
+		cursor.Values = make([]any, len(sorts))
+		for i, s := range sorts {
+			cursor.Values[i] = rows[len(rows)-1][s.Key]
+		}
+	}
+	return ew.Close()
+}
+
+// ExportFields returns the exported fields (the flat json keys) of the model, in order of the struct fields.
+// If the $select query param is set, only the selected fields are returned.
+func (queryUtil) ExportFields(model any, selects string) []string {
+	fields := []string{}
+	var find func(t reflect.Type)
+	find = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				find(f.Type)
+				continue
+			}
+			key, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			db := f.Tag.Get("db")
+			if key != "" && key != "-" && db != "" && db != "-" {
+				fields = append(fields, key)
+			}
+		}
+	}
+	find(reflect.TypeOf(model))
+	if selects == "" {
+		return fields
+	}
+	selected := []string{}
+	for _, s := range strings.Split(selects, ",") {
+		s = strings.TrimSpace(s)
+		for _, f := range fields {
+			if f == s || strings.HasPrefix(f, s+".") {
+				selected = append(selected, f)
+			}
+		}
+	}
+	return selected
+}
+
+func cloneQuery(query url.Values) url.Values {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = append([]string{}, v...)
+	}
+	return q
+}
+
+// exportWriter writes the exported rows with the specific format.
+type exportWriter interface {
+	WriteHeader(header []string) error
+	WriteRow(fields []string, row map[string]any) error
+	Close() error
+}
+
+// exportValue returns the string representation of the exported value.
+func exportValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case fmt.Stringer:
+		return val.String()
+	case map[string]any, []any:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// escapeFormula prefixes the text with ' if it starts with the formula character (CSV injection),
+// so the spreadsheet shows the text instead of running it as a formula. It does nothing if EXPORT_ESCAPE_FORMULA is false.
+func escapeFormula(s string) string {
+	if EXPORT_ESCAPE_FORMULA && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// isExportText returns true if the exported value is the text, the number is not escaped by escapeFormula (for example -5).
+func isExportText(v any) bool {
+	switch v.(type) {
+	case string, []byte:
+		return true
+	}
+	return false
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvExportWriter) WriteHeader(header []string) error {
+	return e.w.Write(header)
+}
+
+func (e *csvExportWriter) WriteRow(fields []string, row map[string]any) error {
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		record[i] = exportValue(row[f])
+		if isExportText(row[f]) {
+			record[i] = escapeFormula(record[i])
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExportWriter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonExportWriter struct {
+	w      *bufio.Writer
+	isFlat bool
+}
+
+func (e *ndjsonExportWriter) WriteHeader(header []string) error {
+	return nil
+}
+
+func (e *ndjsonExportWriter) WriteRow(fields []string, row map[string]any) error {
+	data := map[string]any{}
+	for _, f := range fields {
+		data[f] = row[f]
+	}
+	b, err := json.Marshal(Query().Return(data, e.isFlat))
+	if err != nil {
+		return err
+	}
+	e.w.Write(b)
+	return e.w.WriteByte('\n')
+}
+
+func (e *ndjsonExportWriter) Close() error {
+	return e.w.Flush()
+}
+
+// xlsxExportWriter writes the minimal xlsx (office open xml) workbook with one sheet and inline strings,
+// the sheet is compressed on the fly so the rows don't need to be kept in the memory.
+type xlsxExportWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+func (e *xlsxExportWriter) WriteHeader(header []string) error {
+	files := [][2]string{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+	}
+	for _, f := range files {
+		w, err := e.zw.Create(f[0])
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(w, f[1]); err != nil {
+			return err
+		}
+	}
+	w, err := e.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	e.sheet = w
+	_, err = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return err
+	}
+	values := make([]any, len(header))
+	for i, h := range header {
+		values[i] = h
+	}
+	return e.writeRow(values)
+}
+
+func (e *xlsxExportWriter) WriteRow(fields []string, row map[string]any) error {
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		values[i] = row[f]
+		if isExportText(row[f]) {
+			values[i] = escapeFormula(exportValue(row[f]))
+		}
+	}
+	return e.writeRow(values)
+}
+
+func (e *xlsxExportWriter) writeRow(values []any) error {
+	e.row++
+	b := &strings.Builder{}
+	b.WriteString(`<row r="` + strconv.Itoa(e.row) + `">`)
+	for i, v := range values {
+		ref := xlsxColumn(i) + strconv.Itoa(e.row)
+		switch val := v.(type) {
+		case nil:
+			continue
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			b.WriteString(`<c r="` + ref + `"><v>` + fmt.Sprint(val) + `</v></c>`)
+		case bool:
+			bv := "0"
+			if val {
+				bv = "1"
+			}
+			b.WriteString(`<c r="` + ref + `" t="b"><v>` + bv + `</v></c>`)
+		default:
+			b.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(b, []byte(exportValue(val)))
+			b.WriteString(`</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(e.sheet, b.String())
+	return err
+}
+
+func (e *xlsxExportWriter) Close() error {
+	if _, err := io.WriteString(e.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return e.zw.Close()
+}
+
+// xlsxColumn returns the column name of the index, for example 0 is A, 26 is AA.
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestExportFormat(t *testing.T) {
+	testCases := []struct {
+		accept   string
+		format   string
+		expected string
+	}{
+		{"", "", ""},
+		{"application/json", "", ""},
+		{"text/csv", "", ExportFormatCSV},
+		{"application/json, text/csv", "", ""},
+		{MIMEXLSX + ";q=0.9", "", ExportFormatXLSX},
+		{MIMENDJSON, "", ExportFormatNDJSON},
+		{"application/json", "CSV", ExportFormatCSV},
+		{"text/csv", "pdf", ExportFormatCSV},
+	}
+	for _, tc := range testCases {
+		if res := Query().ExportFormat(tc.accept, tc.format); res != tc.expected {
+			t.Errorf("Expected ExportFormat(%q, %q) [%v], got [%v]", tc.accept, tc.format, tc.expected, res)
+		}
+	}
+}
+
+func TestExportWriter(t *testing.T) {
+	fields := []string{"code", "name", "amount"}
+	rows := []map[string]any{
+		{"code": "A1", "name": "Name, with comma", "amount": 10.5},
+		{"code": "A2", "name": "<b>&</b>", "amount": nil},
+	}
+
+	buf := &bytes.Buffer{}
+	ew := &csvExportWriter{w: csv.NewWriter(buf)}
+	ew.WriteHeader(fields)
+	for _, row := range rows {
+		ew.WriteRow(fields, row)
+	}
+	if err := ew.Close(); err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	expected := "code,name,amount\nA1,\"Name, with comma\",10.5\nA2,<b>&</b>,\n"
+	if buf.String() != expected {
+		t.Errorf("Expected csv [%v], got [%v]", expected, buf.String())
+	}
+
+	buf = &bytes.Buffer{}
+	xw := &xlsxExportWriter{zw: zip.NewWriter(buf)}
+	xw.WriteHeader(fields)
+	for _, row := range rows {
+		xw.WriteRow(fields, row)
+	}
+	if err := xw.Close(); err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected valid zip, got [%v]", err)
+	}
+	sheet := ""
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			b, _ := io.ReadAll(r)
+			sheet = string(b)
+		}
+	}
+	for _, s := range []string{`<c r="C2"><v>10.5</v></c>`, `&lt;b&gt;&amp;&lt;/b&gt;`, `<row r="3">`} {
+		if !strings.Contains(sheet, s) {
+			t.Errorf("Expected sheet contains [%v], got [%v]", s, sheet)
+		}
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for i, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if res := xlsxColumn(i); res != expected {
+			t.Errorf("Expected xlsxColumn(%v) [%v], got [%v]", i, expected, res)
+		}
+	}
+}
+
+func TestExportEscapeFormula(t *testing.T) {
+	fields := []string{"name", "amount"}
+	rows := []map[string]any{
+		{"name": "=HYPERLINK(\"http://evil\")", "amount": -5},
+		{"name": "@SUM(A1)", "amount": 1},
+		{"name": "\tTab", "amount": 2},
+		{"name": "a-b", "amount": 3},
+	}
+
+	buf := &bytes.Buffer{}
+	ew := &csvExportWriter{w: csv.NewWriter(buf)}
+	for _, row := range rows {
+		ew.WriteRow(fields, row)
+	}
+	ew.Close()
+	expected := "\"'=HYPERLINK(\"\"http://evil\"\")\",-5\n'@SUM(A1),1\n'\tTab,2\na-b,3\n"
+	if buf.String() != expected {
+		t.Errorf("Expected csv [%v], got [%v]", expected, buf.String())
+	}
+
+	buf = &bytes.Buffer{}
+	xw := &xlsxExportWriter{zw: zip.NewWriter(buf)}
+	xw.WriteHeader(fields)
+	for _, row := range rows {
+		xw.WriteRow(fields, row)
+	}
+	xw.Close()
+	zr, _ := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	sheet := ""
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			r, _ := f.Open()
+			b, _ := io.ReadAll(r)
+			sheet = string(b)
+		}
+	}
+	for _, s := range []string{`&#39;=HYPERLINK`, `&#39;@SUM(A1)`, `<v>-5</v>`} {
+		if !strings.Contains(sheet, s) {
+			t.Errorf("Expected sheet contains [%v], got [%v]", s, sheet)
+		}
+	}
+
+	EXPORT_ESCAPE_FORMULA = false
+	defer func() { EXPORT_ESCAPE_FORMULA = true }()
+	if s := escapeFormula("=1+1"); s != "=1+1" {
+		t.Errorf("Expected the text is not escaped when EXPORT_ESCAPE_FORMULA is false, got [%v]", s)
+	}
+}
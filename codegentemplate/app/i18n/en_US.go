@@ -3,12 +3,22 @@ package i18n
 func EnUS() map[string]string {
 	return map[string]string{
 		"400_bad_request":              "The request cannot be performed because of malformed or missing parameters.",
+		"400_idempotency_key_invalid":  "The Idempotency-Key header must be 1 to 255 characters.",
 		"401_unauthorized":             "Unauthorized. Please Re-Login",
 		"403_forbidden":                "The user does not have permission to :action.",
 		"404_not_found":                "The resource you have specified cannot be found.",
+		"409_idempotency_key_in_use":   "The request with the same Idempotency-Key is being processed, please try again later.",
+		"412_precondition_failed":      "The data has been changed by another user, please reload the data and try again.",
+		"422_idempotency_key_mismatch": "The Idempotency-Key has been used for a different request.",
+		"428_precondition_required":    "The If-Match header is required to change the data.",
 		"500_internal_error":           "Failed to connect to the server, please try again later.",
+		"cache_prefix_required":        "The prefix of the cache keys to flush is required.",
 		"deleted":                      ":entity data with :key = :value has been deleted.",
 		"entity_key_value_not_found":   ":entity data with :key = :value cannot be found.",
+		"id_required":                  "The id of the item is required.",
+		"import_file_empty":            "The file has no data to import.",
+		"import_file_invalid":          "The file cannot be imported: :err.",
+		"import_file_required":         "The file to import is required.",
 		"invalid_username_or_password": "Invalid username or password",
 	}
 }
@@ -3,12 +3,22 @@ package i18n
 func IdID() map[string]string {
 	return map[string]string{
 		"400_bad_request":              "Permintaan tidak dapat dilakukan karena ada parameter yang salah atau tidak lengkap.",
+		"400_idempotency_key_invalid":  "Header Idempotency-Key harus terdiri dari 1 sampai 255 karakter.",
 		"401_unauthorized":             "Token otentikasi tidak valid. Silakan logout dan login ulang",
 		"403_forbidden":                "Pengguna tidak memiliki izin untuk :action.",
 		"404_not_found":                "The resource you have specified cannot be found.",
+		"409_idempotency_key_in_use":   "Permintaan dengan Idempotency-Key yang sama sedang diproses, silakan coba lagi nanti.",
+		"412_precondition_failed":      "Data telah diubah oleh pengguna lain, silakan muat ulang data dan coba lagi.",
+		"422_idempotency_key_mismatch": "Idempotency-Key telah digunakan untuk permintaan yang berbeda.",
+		"428_precondition_required":    "Header If-Match wajib diisi untuk mengubah data.",
 		"500_internal_error":           "Gagal terhubung ke server, silakan coba lagi nanti.",
+		"cache_prefix_required":        "Prefix dari key cache yang akan dihapus wajib diisi.",
 		"deleted":                      "Data :entity dengan :key = :value telah dihapus.",
 		"entity_key_value_not_found":   "Data :entity dengan :key = :value tidak ditemukan.",
+		"id_required":                  "Id dari item wajib diisi.",
+		"import_file_empty":            "File tidak memiliki data untuk diimpor.",
+		"import_file_invalid":          "File tidak dapat diimpor: :err.",
+		"import_file_required":         "File yang akan diimpor wajib diisi.",
 		"invalid_username_or_password": "Username atau kata sandi tidak valid",
 	}
 }
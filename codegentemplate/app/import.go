@@ -0,0 +1,588 @@
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// These are the status of the import.
+const (
+	ImportStatusPending = "pending"
+	ImportStatusRunning = "running"
+	ImportStatusDone    = "done"
+	ImportStatusFailed  = "failed"
+)
+
+// ImportParam is the expected parameters for the import request, sent as the multipart form fields with the file.
+type ImportParam struct {
+	Mode     string `json:"mode"`       // all_or_nothing (default) or best_effort, see BulkParam
+	IsDryRun bool   `json:"is_dry_run"` // validate all rows without saving the data
+}
+
+// ImportRowError is the error of the row of the imported file.
+type ImportRowError struct {
+	Row     int    `json:"row"` // the row number on the file, the header is the row 1
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Detail  any    `json:"detail,omitempty"`
+}
+
+// ImportPayload is the payload of the import job ("{entity}.import") enqueued by Ctx.Import.
+type ImportPayload struct {
+	ImportID string            `json:"import_id"`
+	Rows     []int             `json:"rows"`
+	Items    []json.RawMessage `json:"items"`
+}
+
+// ImportRecord is the history and the progress of the import.
+type ImportRecord struct {
+	Model
+	ID             NullUUID     `json:"id"              db:"m.id"              gorm:"column:id;primaryKey"`
+	Entity         NullString   `json:"entity"          db:"m.entity"          gorm:"column:entity;index"`
+	FileName       NullString   `json:"file_name"       db:"m.file_name"       gorm:"column:file_name"`
+	Mode           NullString   `json:"mode"            db:"m.mode"            gorm:"column:mode"`
+	IsDryRun       NullBool     `json:"is_dry_run"      db:"m.is_dry_run"      gorm:"column:is_dry_run"`
+	Status         NullString   `json:"status"          db:"m.status"          gorm:"column:status"`
+	TotalCount     NullInt64    `json:"total_count"     db:"m.total_count"     gorm:"column:total_count"`
+	ProcessedCount NullInt64    `json:"processed_count" db:"m.processed_count" gorm:"column:processed_count"`
+	SuccessCount   NullInt64    `json:"success_count"   db:"m.success_count"   gorm:"column:success_count"`
+	FailedCount    NullInt64    `json:"failed_count"    db:"m.failed_count"    gorm:"column:failed_count"`
+	Errors         NullJSON     `json:"errors"          db:"m.errors"          gorm:"column:errors"` // []ImportRowError
+	Error          NullText     `json:"error"           db:"m.error"           gorm:"column:error"`
+	CreatedBy      NullString   `json:"created_by"      db:"m.created_by"      gorm:"column:created_by"`
+	CreatedAt      NullDateTime `json:"created_at"      db:"m.created_at"      gorm:"column:created_at"`
+	FinishedAt     NullDateTime `json:"finished_at"     db:"m.finished_at"     gorm:"column:finished_at"`
+}
+
+// EndPoint returns the ImportRecord end point, it used for cache key, etc.
+func (ImportRecord) EndPoint() string {
+	return "imports"
+}
+
+// TableVersion returns the versions of the ImportRecord table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (ImportRecord) TableVersion() string {
+	return "2026-10-19_14.00"
+}
+
+// TableName returns the name of the ImportRecord table in the database.
+func (ImportRecord) TableName() string {
+	return "imports"
+}
+
+// TableAliasName returns the table alias name of the ImportRecord table, used for querying.
+func (ImportRecord) TableAliasName() string {
+	return "m"
+}
+
+// GetRelations returns the relations of the ImportRecord data in the database, used for querying.
+func (m *ImportRecord) GetRelations() map[string]map[string]any {
+	return m.Relations
+}
+
+// GetFilters returns the filter of the ImportRecord data in the database, used for querying.
+func (m *ImportRecord) GetFilters() []map[string]any {
+	return m.Filters
+}
+
+// GetSorts returns the default sort of the ImportRecord data in the database, used for querying.
+func (m *ImportRecord) GetSorts() []map[string]any {
+	m.AddSort(map[string]any{"column": "m.created_at", "direction": "desc"})
+	return m.Sorts
+}
+
+// GetFields returns list of the field of the ImportRecord data in the database, used for querying.
+func (m *ImportRecord) GetFields() map[string]map[string]any {
+	m.SetFields(m)
+	return m.Fields
+}
+
+// GetSchema returns the ImportRecord schema, used for querying.
+func (m *ImportRecord) GetSchema() map[string]any {
+	return m.SetSchema(m)
+}
+
+// OpenAPISchemaName returns the name of the ImportRecord schema in the open api documentation.
+func (ImportRecord) OpenAPISchemaName() string {
+	return "ImportRecord"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ImportRecord in the open api documentation.
+func (m *ImportRecord) GetOpenAPISchema() map[string]any {
+	return m.SetOpenAPISchema(m)
+}
+
+// StatusCode returns the http status code of the import request.
+// It returns 202 if the import is run on the background, otherwise it follows BulkResult.StatusCode.
+func (m ImportRecord) StatusCode() int {
+	if m.Status.String == ImportStatusPending {
+		return http.StatusAccepted
+	}
+	return BulkResult{Mode: m.Mode.String, FailedCount: int(m.FailedCount.Int64)}.StatusCode(http.StatusOK)
+}
+
+type ImportRecordList struct {
+	ListModel
+	Data []ImportRecord `json:"results"`
+}
+
+// OpenAPISchemaName returns the name of the ImportRecordList schema in the open api documentation.
+func (ImportRecordList) OpenAPISchemaName() string {
+	return "ImportRecordList"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ImportRecordList in the open api documentation.
+func (p *ImportRecordList) GetOpenAPISchema() map[string]any {
+	return p.SetOpenAPISchema(&ImportRecord{})
+}
+
+// ImportFile is the multipart request body of the import, it is used in the open api documentation.
+type ImportFile struct{}
+
+// OpenAPISchemaName returns the name of the ImportFile schema in the open api documentation.
+func (ImportFile) OpenAPISchemaName() string {
+	return "ImportFile"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ImportFile in the open api documentation.
+func (ImportFile) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"file": map[string]any{"type": "string", "format": "binary", "description": "The csv or xlsx file, the first row is the header"},
+			"mode": map[string]any{
+				"type":    "string",
+				"enum":    []string{BulkModeAllOrNothing, BulkModeBestEffort},
+				"default": BulkModeAllOrNothing,
+			},
+			"is_dry_run": map[string]any{"type": "boolean", "default": false},
+		},
+		"required": []string{"file"},
+	}
+}
+
+// Import imports the uploaded csv or xlsx file of the entity, each row is passed to fn like the bulk item (see RunBulk).
+// The columns are mapped to the fields of the model by the json name or the translated name (the header of the export).
+// If the file has more than IMPORT_ASYNC_THRESHOLD rows, the import is run on the background with the "{entity}.import" job
+// and the progress can be polled with the returned id, otherwise the import is run immediately.
+// The import is saved to the imports table, with the error report of the failed rows.
+func (c Ctx) Import(entity string, file *multipart.FileHeader, param ImportParam, model any, fn func(ctx Ctx, item []byte) (id string, err error)) (ImportRecord, error) {
+	rec := ImportRecord{}
+	if param.Mode == "" {
+		param.Mode = BulkModeAllOrNothing
+	}
+	if param.Mode != BulkModeAllOrNothing && param.Mode != BulkModeBestEffort {
+		return rec, Error().New(http.StatusBadRequest, "mode must be "+BulkModeAllOrNothing+" or "+BulkModeBestEffort)
+	}
+	if file == nil {
+		return rec, Error().New(http.StatusBadRequest, c.Trans("import_file_required"))
+	}
+	if IMPORT_MAX_SIZE_MB > 0 && file.Size > int64(IMPORT_MAX_SIZE_MB)<<20 {
+		return rec, Error().New(http.StatusRequestEntityTooLarge, c.Trans("import_file_invalid", map[string]string{"err": errImportTooLarge.Error()}))
+	}
+	f, err := file.Open()
+	if err != nil {
+		return rec, Error().New(http.StatusBadRequest, err.Error())
+	}
+	defer f.Close()
+	rows, items, err := Query().ReadImportFile(file.Filename, f, model, c.Lang)
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, errImportTooLarge) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		return rec, Error().New(code, c.Trans("import_file_invalid", map[string]string{"err": err.Error()}))
+	}
+	if len(items) == 0 {
+		return rec, Error().New(http.StatusBadRequest, c.Trans("import_file_empty"))
+	}
+
+	rec.ID = NewNullUUID()
+	rec.Entity = NewNullString(entity)
+	rec.FileName = NewNullString(file.Filename)
+	rec.Mode = NewNullString(param.Mode)
+	rec.IsDryRun = NewNullBool(param.IsDryRun)
+	rec.Status = NewNullString(ImportStatusPending)
+	rec.TotalCount = NewNullInt64(int64(len(items)))
+	rec.ProcessedCount = NewNullInt64(0)
+	rec.CreatedAt = NewNullDateTime(time.Now().UTC())
+	if c.UserID != "" {
+		rec.CreatedBy = NewNullString(c.UserID)
+	}
+	db, err := DB().Conn("main")
+	if err != nil {
+		return rec, Error().New(http.StatusInternalServerError, err.Error())
+	}
+	if err = db.Create(&rec).Error; err != nil {
+		return rec, Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	if len(items) > IMPORT_ASYNC_THRESHOLD {
+		return rec, Job().Enqueue(c, entity+".import", ImportPayload{ImportID: rec.ID.String, Rows: rows, Items: items})
+	}
+	c.runImport(&rec, rows, items, fn)
+	if rec.Status.String == ImportStatusFailed {
+		return rec, Error().New(http.StatusInternalServerError, rec.Error.String)
+	}
+	return rec, nil
+}
+
+// RunImportJob runs the import enqueued by Import, it is called by the "{entity}.import" job handler.
+// The failed import is not retried, the error is saved to the import instead.
+func (c Ctx) RunImportJob(p ImportPayload, fn func(ctx Ctx, item []byte) (id string, err error)) error {
+	db, err := DB().Conn("main")
+	if err != nil {
+		return err
+	}
+	rec := ImportRecord{}
+	if err = db.Where("id = ?", p.ImportID).Take(&rec).Error; err != nil {
+		return err
+	}
+	if rec.Status.String != ImportStatusPending {
+		return nil // already run by another attempt
+	}
+	c.runImport(&rec, p.Rows, p.Items, fn)
+	return nil
+}
+
+// runImport runs the import rows with RunBulk, saves the progress every 100 rows and saves the result.
+func (c Ctx) runImport(rec *ImportRecord, rows []int, items []json.RawMessage, fn func(ctx Ctx, item []byte) (id string, err error)) {
+	save := func(values map[string]any) {
+		db, err := DB().Conn("main")
+		if err == nil {
+			err = db.Model(&ImportRecord{}).Where("id = ?", rec.ID).Updates(values).Error
+		}
+		if err != nil {
+			Logger().Error("Failed to save import", Logger().Attrs(c, []any{slog.String("import_id", rec.ID.String), slog.Any("err", err)})...)
+		}
+	}
+	rec.Status = NewNullString(ImportStatusRunning)
+	save(map[string]any{"status": rec.Status})
+
+	res, err := c.RunBulk(BulkParam{
+		Mode:     rec.Mode.String,
+		Items:    items,
+		IsDryRun: rec.IsDryRun.Bool,
+		isImport: true,
+		OnItem: func(res BulkResult) {
+			if processed := len(res.Results); processed%100 == 0 {
+				save(map[string]any{"processed_count": processed})
+			}
+		},
+	}, fn)
+
+	rowErrors := []ImportRowError{}
+	for _, r := range res.Results {
+		if r.Code >= http.StatusBadRequest {
+			rowErrors = append(rowErrors, ImportRowError{Row: rows[r.Index], Code: r.Code, Message: r.Message, Detail: r.Detail})
+		}
+	}
+	rec.Status = NewNullString(ImportStatusDone)
+	if err != nil {
+		rec.Status = NewNullString(ImportStatusFailed)
+		rec.Error = NewNullText(err.Error())
+	}
+	rec.ProcessedCount = NewNullInt64(int64(len(res.Results)))
+	rec.SuccessCount = NewNullInt64(int64(res.SuccessCount))
+	rec.FailedCount = NewNullInt64(int64(res.FailedCount))
+	if res.Mode == BulkModeAllOrNothing && res.FailedCount > 0 && !rec.IsDryRun.Bool {
+		rec.SuccessCount = NewNullInt64(0) // rolled back
+	}
+	rec.Errors = NullJSON{}
+	rec.Errors.Data = rowErrors
+	rec.Errors.Valid = true
+	rec.FinishedAt = NewNullDateTime(time.Now().UTC())
+	save(map[string]any{
+		"status":          rec.Status,
+		"error":           rec.Error,
+		"processed_count": rec.ProcessedCount,
+		"success_count":   rec.SuccessCount,
+		"failed_count":    rec.FailedCount,
+		"errors":          rec.Errors,
+		"finished_at":     rec.FinishedAt,
+	})
+}
+
+var errImportTooLarge = errors.New("the file is too large")
+
+// ReadImportFile reads the csv or xlsx file (based on the file extension) and returns the rows as the flat json objects of the model.
+// The first row is the header, the column is mapped to the field of the model by the json name or the translated name (case insensitive),
+// the unknown column and the empty cell are ignored. It also returns the row number of each item on the file to report the error.
+// The file larger than IMPORT_MAX_SIZE_MB or with more rows than IMPORT_MAX_ROWS or more columns than IMPORT_MAX_COLUMNS is rejected.
+func (q queryUtil) ReadImportFile(fileName string, r io.Reader, model any, lang string) ([]int, []json.RawMessage, error) {
+	ext := strings.ToLower(path.Ext(fileName))
+	if ext != ".csv" && ext != ".xlsx" {
+		return nil, nil, errors.New("the file must be csv or xlsx")
+	}
+	if IMPORT_MAX_SIZE_MB > 0 {
+		r = io.LimitReader(r, int64(IMPORT_MAX_SIZE_MB)<<20+1)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if IMPORT_MAX_SIZE_MB > 0 && len(b) > IMPORT_MAX_SIZE_MB<<20 {
+		return nil, nil, errImportTooLarge
+	}
+	var records [][]string
+	if ext == ".csv" {
+		cr := csv.NewReader(bytes.NewReader(b))
+		cr.FieldsPerRecord = -1
+		records, err = cr.ReadAll()
+	} else {
+		records, err = readXLSX(b)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if IMPORT_MAX_ROWS > 0 && len(records) > IMPORT_MAX_ROWS+1 {
+		return nil, nil, errors.New("the file must not have more than " + strconv.Itoa(IMPORT_MAX_ROWS) + " rows")
+	}
+	for _, record := range records {
+		if IMPORT_MAX_COLUMNS > 0 && len(record) > IMPORT_MAX_COLUMNS {
+			return nil, nil, errors.New("the file must not have more than " + strconv.Itoa(IMPORT_MAX_COLUMNS) + " columns")
+		}
+	}
+	if len(records) == 0 {
+		return []int{}, []json.RawMessage{}, nil
+	}
+
+	types := map[string]reflect.Type{}
+	names := map[string]string{}
+	for _, f := range q.ExportFields(model, "") {
+		names[strings.ToLower(f)] = f
+		names[strings.ToLower(Translator().Trans(lang, f))] = f
+	}
+	importFieldTypes(reflect.TypeOf(model), types)
+	columns := make([]string, len(records[0]))
+	isMapped := false
+	for i, h := range records[0] {
+		columns[i] = names[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))]
+		isMapped = isMapped || columns[i] != ""
+	}
+	if !isMapped {
+		return nil, nil, errors.New("no column of the header matches the fields")
+	}
+
+	rows := []int{}
+	items := []json.RawMessage{}
+	for i, record := range records[1:] {
+		item := map[string]any{}
+		for j, v := range record {
+			if j < len(columns) && columns[j] != "" && strings.TrimSpace(v) != "" {
+				item[columns[j]] = importValue(types[columns[j]], strings.TrimSpace(v))
+			}
+		}
+		if len(item) == 0 {
+			continue // skip the empty row
+		}
+		b, err := json.Marshal(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, i+2)
+		items = append(items, b)
+	}
+	return rows, items, nil
+}
+
+// importFieldTypes collects the type of the model fields by the json name.
+func importFieldTypes(t reflect.Type, types map[string]reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			importFieldTypes(f.Type, types)
+			continue
+		}
+		if key, _, _ := strings.Cut(f.Tag.Get("json"), ","); key != "" && key != "-" {
+			types[key] = f.Type
+		}
+	}
+}
+
+// importValue converts the cell value to the json value based on the field type,
+// the value is kept as string if it is not valid, so the error is reported by the validation.
+func importValue(t reflect.Type, v string) any {
+	name := ""
+	if t != nil {
+		name = t.Name()
+	}
+	switch {
+	case strings.Contains(name, "Int") || strings.Contains(name, "Float"):
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return json.Number(v)
+		}
+	case strings.Contains(name, "Bool"):
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case strings.Contains(name, "JSON"):
+		if json.Valid([]byte(v)) {
+			return json.RawMessage(v)
+		}
+	}
+	return v
+}
+
+// readXLSX reads the cells of the first sheet of the xlsx file.
+// The date cell must be formatted as text, since it is saved as the serial number on the xlsx.
+// The row and column refs above IMPORT_MAX_ROWS and IMPORT_MAX_COLUMNS are rejected before the empty cells are filled.
+func readXLSX(b []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	decode := func(name string, v any) error {
+		f, ok := files[name]
+		if !ok {
+			return errors.New(name + " is not found")
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		var r io.Reader = rc
+		if IMPORT_MAX_SIZE_MB > 0 {
+			r = io.LimitReader(rc, int64(IMPORT_MAX_SIZE_MB)<<20*10) // the xml is usually compressed about 10 times on the xlsx
+		}
+		return xml.NewDecoder(r).Decode(v)
+	}
+
+	// find the first sheet from the workbook
+	workbook := struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}{}
+	rels := struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}{}
+	if err = decode("xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if err = decode("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	sheetName := ""
+	for _, rel := range rels.Relationships {
+		if len(workbook.Sheets) > 0 && rel.ID == workbook.Sheets[0].RID {
+			sheetName = strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(sheetName, "xl/") {
+				sheetName = "xl/" + sheetName
+			}
+		}
+	}
+
+	type richText struct {
+		T string `xml:"t"`
+		R []struct {
+			T string `xml:"t"`
+		} `xml:"r"`
+	}
+	text := func(rt richText) string {
+		s := rt.T
+		for _, r := range rt.R {
+			s += r.T
+		}
+		return s
+	}
+	sharedStrings := struct {
+		SI []richText `xml:"si"`
+	}{}
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err = decode("xl/sharedStrings.xml", &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+	sheet := struct {
+		Rows []struct {
+			Ref   int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline richText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}{}
+	if err = decode(sheetName, &sheet); err != nil {
+		return nil, err
+	}
+
+	records := [][]string{}
+	for _, row := range sheet.Rows {
+		if IMPORT_MAX_ROWS > 0 && row.Ref > IMPORT_MAX_ROWS+1 {
+			return nil, errors.New("the file must not have more than " + strconv.Itoa(IMPORT_MAX_ROWS) + " rows")
+		}
+		for row.Ref > len(records)+1 {
+			records = append(records, []string{}) // the empty row is not saved on the xlsx
+		}
+		record := []string{}
+		for _, cell := range row.Cells {
+			col := len(record)
+			if cell.Ref != "" {
+				col = xlsxColumnIndex(cell.Ref)
+			}
+			if col < 0 || (IMPORT_MAX_COLUMNS > 0 && col >= IMPORT_MAX_COLUMNS) {
+				return nil, errors.New("invalid cell reference " + cell.Ref)
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			switch cell.Type {
+			case "s":
+				if i, err := strconv.Atoi(cell.Value); err == nil && i >= 0 && i < len(sharedStrings.SI) {
+					record[col] = text(sharedStrings.SI[i])
+				}
+			case "inlineStr":
+				record[col] = text(cell.Inline)
+			case "b":
+				record[col] = strconv.FormatBool(cell.Value == "1")
+			default:
+				record[col] = cell.Value
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// xlsxColumnIndex returns the column index of the cell reference, for example A1 is 0, AA1 is 26.
+// It returns -1 for the reference without the column or with the column beyond the xlsx limit (XFD).
+func xlsxColumnIndex(ref string) int {
+	i := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		i = i*26 + int(r-'A'+1)
+		if i > 16384 {
+			return -1
+		}
+	}
+	return i - 1
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type importTestModel struct {
+	Model
+	Code     NullString  `json:"code"      db:"m.code"`
+	Qty      NullInt64   `json:"qty"       db:"m.qty"`
+	IsActive NullBool    `json:"is_active" db:"m.is_active"`
+	Note     NullText    `json:"note"      db:"-"`
+	Price    NullFloat64 `json:"price"     db:"m.price"`
+}
+
+func TestReadImportFile(t *testing.T) {
+	expected := []string{
+		`{"code":"007","is_active":true,"qty":10}`,
+		`{"code":"A2","price":1.5,"qty":"ten"}`,
+	}
+
+	csvFile := "\ufeffCode,qty,is_active,unknown,price\n007,10,true,x,\n,,,,\nA2,ten,,,1.5\n"
+	rows, items, err := Query().ReadImportFile("data.CSV", strings.NewReader(csvFile), &importTestModel{}, "en")
+	if err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	if len(rows) != 2 || rows[0] != 2 || rows[1] != 4 {
+		t.Errorf("Expected rows [2 4], got %v", rows)
+	}
+	for i, item := range items {
+		if string(item) != expected[i] {
+			t.Errorf("Expected item %v [%v], got [%v]", i, expected[i], string(item))
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	xw := &xlsxExportWriter{zw: zip.NewWriter(buf)}
+	fields := []string{"code", "qty", "is_active", "price"}
+	xw.WriteHeader(fields)
+	xw.WriteRow(fields, map[string]any{"code": "007", "qty": 10, "is_active": true})
+	xw.WriteRow(fields, map[string]any{})
+	xw.WriteRow(fields, map[string]any{"code": "A2", "qty": "ten", "price": 1.5})
+	xw.Close()
+	rows, items, err = Query().ReadImportFile("data.xlsx", buf, &importTestModel{}, "en")
+	if err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	if len(rows) != 2 || rows[0] != 2 || rows[1] != 4 {
+		t.Errorf("Expected rows [2 4], got %v", rows)
+	}
+	for i, item := range items {
+		if string(item) != expected[i] {
+			t.Errorf("Expected item %v [%v], got [%v]", i, expected[i], string(item))
+		}
+	}
+
+	if _, _, err = Query().ReadImportFile("data.pdf", strings.NewReader(""), &importTestModel{}, "en"); err == nil {
+		t.Errorf("Expected error for unsupported file")
+	}
+	if _, _, err = Query().ReadImportFile("data.csv", strings.NewReader("a,b\n1,2\n"), &importTestModel{}, "en"); err == nil {
+		t.Errorf("Expected error for unknown header")
+	}
+}
+
+func TestReadImportFileLimit(t *testing.T) {
+	sheet := func(rows string) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		files := map[string]string{
+			"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+			"xl/sharedStrings.xml":       `<sst><si><t>code</t></si></sst>`,
+			"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + rows + `</sheetData></worksheet>`,
+		}
+		for name, content := range files {
+			w, _ := zw.Create(name)
+			w.Write([]byte(content))
+		}
+		zw.Close()
+		return buf
+	}
+
+	_, items, err := Query().ReadImportFile("data.xlsx", sheet(`<row r="1"><c r="A1" t="s"><v>0</v></c></row><row r="2"><c r="A2" t="s"><v>-1</v></c><c r="B2"><v>x</v></c></row><row r="3"><c r="A3"><v>007</v></c></row>`), &importTestModel{}, "en")
+	if err != nil {
+		t.Fatalf("Expected no error for negative shared string index, got [%v]", err)
+	}
+	if len(items) != 1 || string(items[0]) != `{"code":"007"}` {
+		t.Errorf("Expected the negative shared string index to be ignored, got %v", items)
+	}
+
+	oldRows, oldColumns, oldSize := IMPORT_MAX_ROWS, IMPORT_MAX_COLUMNS, IMPORT_MAX_SIZE_MB
+	defer func() { IMPORT_MAX_ROWS, IMPORT_MAX_COLUMNS, IMPORT_MAX_SIZE_MB = oldRows, oldColumns, oldSize }()
+	IMPORT_MAX_ROWS, IMPORT_MAX_COLUMNS, IMPORT_MAX_SIZE_MB = 10, 5, 1
+
+	if _, _, err = Query().ReadImportFile("data.xlsx", sheet(`<row r="1"><c r="A1" t="s"><v>0</v></c></row><row r="2147483647"><c r="A2147483647"><v>1</v></c></row>`), &importTestModel{}, "en"); err == nil {
+		t.Errorf("Expected error for oversized row ref")
+	}
+	if _, _, err = Query().ReadImportFile("data.xlsx", sheet(`<row r="1"><c r="XFD1" t="s"><v>0</v></c></row>`), &importTestModel{}, "en"); err == nil {
+		t.Errorf("Expected error for oversized column ref")
+	}
+	if _, _, err = Query().ReadImportFile("data.xlsx", sheet(`<row r="1"><c r="ZZZZZZZZZZZZZZ1" t="s"><v>0</v></c></row>`), &importTestModel{}, "en"); err == nil {
+		t.Errorf("Expected error for overflowed column ref")
+	}
+	if _, _, err = Query().ReadImportFile("data.csv", strings.NewReader("code\n"+strings.Repeat("1\n", 11)), &importTestModel{}, "en"); err == nil {
+		t.Errorf("Expected error for too many csv rows")
+	}
+	if _, _, err = Query().ReadImportFile("data.csv", strings.NewReader("code,"+strings.Repeat(",", 5)+"\n"), &importTestModel{}, "en"); err == nil {
+		t.Errorf("Expected error for too many csv columns")
+	}
+	if _, _, err = Query().ReadImportFile("data.csv", strings.NewReader("code\n"+strings.Repeat("1", 1<<20)), &importTestModel{}, "en"); !errors.Is(err, errImportTooLarge) {
+		t.Errorf("Expected errImportTooLarge, got [%v]", err)
+	}
+}
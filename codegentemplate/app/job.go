@@ -0,0 +1,247 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Job returns a pointer to the jobUtil instance (jq).
+// If jq is not initialized, it creates a new jobUtil instance, configures it, and assigns it to jq.
+// It ensures that only one instance of jobUtil is created and reused.
+func Job() *jobUtil {
+	if jq == nil {
+		jq = &jobUtil{}
+		jq.configure()
+	}
+	return jq
+}
+
+// jq is a pointer to a jobUtil instance.
+// It is used to store and access the singleton instance of jobUtil.
+var jq *jobUtil
+
+// jobUtil represents a durable background job queue.
+// The jobs are saved to the Store and run by a pool of workers, failed jobs are retried with exponential backoff
+// and moved to the dead letter after MaxAttempts.
+type jobUtil struct {
+	Store         JobStore
+	Workers       int
+	PollInterval  time.Duration
+	MaxAttempts   int
+	RetryInterval time.Duration
+	LockTimeout   time.Duration
+	Retention     time.Duration
+	handlers      map[string]func(Ctx, []byte) error
+	workerID      string
+	quit          chan struct{}
+	wg            *sync.WaitGroup
+}
+
+// configure configures the job utility instance.
+// It uses redis store if JOB_STORE is "redis" and the cache is connected to redis, otherwise it uses db store.
+func (j *jobUtil) configure() {
+	j.Workers = JOB_WORKERS
+	j.PollInterval = JOB_POLL_INTERVAL
+	j.MaxAttempts = JOB_MAX_ATTEMPTS
+	j.RetryInterval = JOB_RETRY_INTERVAL
+	j.LockTimeout = JOB_LOCK_TIMEOUT
+	j.Retention = JOB_RETENTION
+	j.handlers = map[string]func(Ctx, []byte) error{}
+	hostname, _ := os.Hostname()
+	j.workerID = hostname + "-" + Crypto().NewToken()[:8]
+	j.wg = &sync.WaitGroup{}
+	j.Store = &jobDBStore{}
+	if JOB_STORE == "redis" && Cache().IsUseRedis {
+		j.Store = &jobRedisStore{}
+	}
+	j.handlers["webhook.deliver"] = jobHandler(Webhook().deliverJob)
+}
+
+// RegisterJob registers the typed handler of the job name.
+// The payload of the enqueued job is decoded from json to T before calling the handler.
+// The handler is called with async Ctx of the request that enqueued the job.
+func RegisterJob[T any](name string, handler func(ctx Ctx, payload T) error) {
+	Job().handlers[name] = jobHandler(handler)
+}
+
+// jobHandler wraps the typed handler to the handler with json encoded payload.
+func jobHandler[T any](handler func(ctx Ctx, payload T) error) func(Ctx, []byte) error {
+	return func(ctx Ctx, payload []byte) error {
+		var p T
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		return handler(ctx, p)
+	}
+}
+
+// JobOption is the optional parameters to enqueue the job.
+type JobOption struct {
+	RunAt       time.Time // run the job at the specified time instead of immediately
+	MaxAttempts int       // override JOB_MAX_ATTEMPTS
+}
+
+// Enqueue saves the job to the store to be run by the workers.
+// With db store, the job is saved using the db transaction of the ctx, so the job is only run if the transaction is committed
+// and discarded if it is rolled back. With redis store, the job is pushed after the transaction is committed.
+func (j *jobUtil) Enqueue(c Ctx, name string, payload any, opts ...JobOption) error {
+	if _, ok := j.handlers[name]; !ok {
+		return Error().New(http.StatusInternalServerError, "job "+name+" is not registered")
+	}
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return Error().New(http.StatusInternalServerError, err.Error())
+	}
+	ctxData, _ := json.Marshal(map[string]string{
+		"lang":    c.Lang,
+		"user_id": c.UserID,
+		"method":  c.Action.Method,
+		"path":    c.Action.Path,
+	})
+	now := time.Now().UTC()
+	job := &JobRecord{}
+	job.ID = NewNullUUID()
+	job.Name = NewNullString(name)
+	job.Payload = NewNullText(string(p))
+	job.Ctx = NewNullText(string(ctxData))
+	job.Status = NewNullString(JobStatusPending)
+	job.Attempts = NewNullInt64(0)
+	job.MaxAttempts = NewNullInt64(int64(j.MaxAttempts))
+	job.RunAt = NewNullDateTime(now)
+	job.CreatedAt = NewNullDateTime(now)
+	job.UpdatedAt = NewNullDateTime(now)
+	if len(opts) > 0 {
+		if !opts[0].RunAt.IsZero() {
+			job.RunAt = NewNullDateTime(opts[0].RunAt.UTC())
+		}
+		if opts[0].MaxAttempts > 0 {
+			job.MaxAttempts = NewNullInt64(int64(opts[0].MaxAttempts))
+		}
+	}
+	if err = j.Store.Push(c, job); err != nil {
+		return Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
+// Start starts the pool of workers, it does nothing if JOB_WORKERS is 0.
+func (j *jobUtil) Start() {
+	j.quit = make(chan struct{})
+	for i := 0; i < j.Workers; i++ {
+		j.wg.Add(1)
+		go j.work()
+	}
+}
+
+// PurgeTask is the scheduled task to delete the done and dead jobs older than Retention, it is registered on src/scheduler.go.
+func (j *jobUtil) PurgeTask(ctx Ctx) error {
+	if j.Retention <= 0 {
+		return nil
+	}
+	count, err := j.Store.Purge(time.Now().UTC().Add(-j.Retention))
+	if err != nil {
+		return err
+	}
+	Logger().Info("Jobs are purged", slog.Int64("count", count))
+	return nil
+}
+
+// Stop stops the workers from claiming new jobs and waits for running jobs to complete or until ctx is done.
+// The unfinished jobs are claimed again by another worker after LockTimeout.
+func (j *jobUtil) Stop(ctx context.Context) {
+	if j.quit == nil {
+		return
+	}
+	close(j.quit)
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// work claims and runs the jobs until the worker is stopped.
+func (j *jobUtil) work() {
+	defer j.wg.Done()
+	for {
+		select {
+		case <-j.quit:
+			return
+		default:
+		}
+		jobs, err := j.Store.Claim(j.workerID, 1)
+		if err != nil {
+			Logger().Error("Failed to claim job", slog.Any("err", err))
+		}
+		for _, job := range jobs {
+			j.run(job)
+		}
+		if len(jobs) == 0 {
+			select {
+			case <-j.quit:
+				return
+			case <-time.After(j.PollInterval):
+			}
+		}
+	}
+}
+
+// run runs the claimed job, then marks it as done, retries it with exponential backoff or moves it to the dead letter.
+func (j *jobUtil) run(job JobRecord) {
+	ctx := job.NewCtx()
+	err := j.call(ctx, job)
+	if err == nil {
+		err = j.Store.Complete(job)
+		if err != nil {
+			Logger().Error("Failed to complete job", slog.String("job", job.Name.String), slog.Any("err", err))
+		}
+		return
+	}
+	attrs := Logger().Attrs(ctx, []any{
+		slog.String("job", job.Name.String),
+		slog.String("job_id", job.ID.String),
+		slog.Int64("attempts", job.Attempts.Int64),
+		slog.Any("err", err),
+	})
+	if job.Attempts.Int64 >= job.MaxAttempts.Int64 {
+		Logger().Error("Job is moved to the dead letter", attrs...)
+		err = j.Store.Dead(job, err)
+	} else {
+		Logger().Warn("Job is failed and will be retried", attrs...)
+		err = j.Store.Retry(job, time.Now().UTC().Add(j.Backoff(int(job.Attempts.Int64))), err)
+	}
+	if err != nil {
+		Logger().Error("Failed to save job", slog.String("job", job.Name.String), slog.Any("err", err))
+	}
+}
+
+// call calls the handler of the job, it catch and recover from panics as an error.
+func (j *jobUtil) call(ctx Ctx, job JobRecord) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	handler, ok := j.handlers[job.Name.String]
+	if !ok {
+		return fmt.Errorf("job %s is not registered", job.Name.String)
+	}
+	return handler(ctx, []byte(job.Payload.String))
+}
+
+// Backoff returns the wait duration before the next attempt, it is doubled on every retry.
+func (j *jobUtil) Backoff(attempt int) time.Duration {
+	return time.Duration(float64(j.RetryInterval) * math.Pow(2, float64(attempt-1)))
+}
@@ -0,0 +1,315 @@
+package app
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// These are the status of the job.
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusDead    = "dead"
+)
+
+// JobStore is the storage of the job queue.
+type JobStore interface {
+	Push(c Ctx, job *JobRecord) error
+	Claim(workerID string, limit int) ([]JobRecord, error)
+	Complete(job JobRecord) error
+	Retry(job JobRecord, runAt time.Time, err error) error
+	Dead(job JobRecord, err error) error
+	Purge(before time.Time) (int64, error)
+}
+
+// JobRecord is the job of the job queue.
+type JobRecord struct {
+	ID          NullUUID     `json:"id"           gorm:"column:id;primaryKey"`
+	Name        NullString   `json:"name"         gorm:"column:name"`
+	Payload     NullText     `json:"payload"      gorm:"column:payload"`
+	Ctx         NullText     `json:"ctx"          gorm:"column:ctx"`
+	Status      NullString   `json:"status"       gorm:"column:status;index:idx_jobs_status_run_at"`
+	Attempts    NullInt64    `json:"attempts"     gorm:"column:attempts"`
+	MaxAttempts NullInt64    `json:"max_attempts" gorm:"column:max_attempts"`
+	RunAt       NullDateTime `json:"run_at"       gorm:"column:run_at;index:idx_jobs_status_run_at"`
+	LastError   NullText     `json:"last_error"   gorm:"column:last_error"`
+	LockedBy    NullString   `json:"locked_by"    gorm:"column:locked_by"`
+	LockedAt    NullDateTime `json:"locked_at"    gorm:"column:locked_at"`
+	CreatedAt   NullDateTime `json:"created_at"   gorm:"column:created_at"`
+	UpdatedAt   NullDateTime `json:"updated_at"   gorm:"column:updated_at"`
+}
+
+// TableVersion returns the versions of the JobRecord table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (JobRecord) TableVersion() string {
+	return "2026-10-19_12.00"
+}
+
+// TableName returns the name of the JobRecord table in the database.
+func (JobRecord) TableName() string {
+	return "jobs"
+}
+
+// NewCtx returns the async Ctx to run the job, based on the Ctx of the request that enqueued the job.
+func (job JobRecord) NewCtx() Ctx {
+	data := map[string]string{}
+	json.Unmarshal([]byte(job.Ctx.String), &data)
+	return Ctx{
+		Lang:    data["lang"],
+		UserID:  data["user_id"],
+		IsAsync: true,
+		Action: Action{
+			Method: data["method"],
+			Path:   data["path"],
+		},
+	}
+}
+
+// jobDBStore is the JobStore which saves the jobs to the jobs table of the main db.
+type jobDBStore struct{}
+
+// Push saves the job using the db transaction of the ctx.
+func (*jobDBStore) Push(c Ctx, job *JobRecord) error {
+	tx, err := c.DB()
+	if err != nil {
+		return err
+	}
+	return tx.Create(job).Error
+}
+
+// Claim locks the pending jobs which are due (and the running jobs which lock is expired) for the worker.
+// Each job is locked with conditional update, so it is safe to claim from multiple instances.
+func (*jobDBStore) Claim(workerID string, limit int) ([]JobRecord, error) {
+	jobs := []JobRecord{}
+	tx, err := DB().Conn("main")
+	if err != nil {
+		return jobs, err
+	}
+	now := time.Now().UTC()
+	claimable := func(db *gorm.DB) *gorm.DB {
+		return db.Where("(status = ? and run_at <= ?) or (status = ? and locked_at < ?)",
+			JobStatusPending, now, JobStatusRunning, now.Add(-Job().LockTimeout))
+	}
+	ids := []string{}
+	err = tx.Model(&JobRecord{}).Scopes(claimable).Order("run_at").Limit(limit).Pluck("id", &ids).Error
+	if err != nil {
+		return jobs, err
+	}
+	for _, id := range ids {
+		res := tx.Model(&JobRecord{}).Scopes(claimable).Where("id = ?", id).Updates(map[string]any{
+			"status":     JobStatusRunning,
+			"locked_by":  workerID,
+			"locked_at":  now,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"updated_at": now,
+		})
+		if res.Error != nil {
+			return jobs, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue // claimed by another worker
+		}
+		job := JobRecord{}
+		if err = tx.Where("id = ?", id).Take(&job).Error; err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Complete marks the job as done.
+func (s *jobDBStore) Complete(job JobRecord) error {
+	return s.update(job, map[string]any{"status": JobStatusDone})
+}
+
+// Retry marks the job as pending to be claimed again at runAt.
+func (s *jobDBStore) Retry(job JobRecord, runAt time.Time, err error) error {
+	return s.update(job, map[string]any{"status": JobStatusPending, "run_at": runAt, "last_error": err.Error()})
+}
+
+// Dead moves the job to the dead letter, it can be retried manually by updating the status to pending.
+func (s *jobDBStore) Dead(job JobRecord, err error) error {
+	return s.update(job, map[string]any{"status": JobStatusDead, "last_error": err.Error()})
+}
+
+// Purge deletes the done and dead jobs which are updated before the time.
+func (*jobDBStore) Purge(before time.Time) (int64, error) {
+	tx, err := DB().Conn("main")
+	if err != nil {
+		return 0, err
+	}
+	res := tx.Where("status in ?", []string{JobStatusDone, JobStatusDead}).Where("updated_at < ?", before).Delete(&JobRecord{})
+	return res.RowsAffected, res.Error
+}
+
+func (*jobDBStore) update(job JobRecord, val map[string]any) error {
+	tx, err := DB().Conn("main")
+	if err != nil {
+		return err
+	}
+	val["locked_by"] = nil
+	val["locked_at"] = nil
+	val["updated_at"] = time.Now().UTC()
+	return tx.Model(&JobRecord{}).Where("id = ?", job.ID).Updates(val).Error
+}
+
+// These are the redis keys of the jobRedisStore.
+const (
+	jobRedisQueueKey = "jobs:queue" // sorted set of job id with run at (unix milli) as the score
+	jobRedisDataKey  = "jobs:data"  // hash of job id and json encoded job
+	jobRedisDeadKey  = "jobs:dead"  // list of json encoded dead job
+)
+
+// jobRedisClaimScript claims the job if it is due by moving its score to the lock expiration time.
+var jobRedisClaimScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if score and tonumber(score) <= tonumber(ARGV[2]) then
+	redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// jobRedisStore is the JobStore which saves the jobs to the redis of the cache.
+// The running job stays in the queue with its lock expiration time as the score, so it is claimed again if the worker is died.
+type jobRedisStore struct{}
+
+// Push pushes the job to the queue after the db transaction of the ctx is committed.
+func (s *jobRedisStore) Push(c Ctx, job *JobRecord) error {
+	j := *job
+	c.AfterCommit(func() {
+		if err := s.save(j, float64(j.RunAt.Time.UnixMilli())); err != nil {
+			Logger().Error("Failed to push job", Logger().Attrs(c, []any{slog.String("job", j.Name.String), slog.Any("err", err)})...)
+		}
+	})
+	return nil
+}
+
+// Claim claims the jobs which are due for the worker.
+func (s *jobRedisStore) Claim(workerID string, limit int) ([]JobRecord, error) {
+	jobs := []JobRecord{}
+	rc, ctx := Cache().RedisClient, Cache().Ctx
+	now := time.Now().UTC()
+	ids, err := rc.ZRangeByScore(ctx, jobRedisQueueKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return jobs, err
+	}
+	lockUntil := now.Add(Job().LockTimeout).UnixMilli()
+	for _, id := range ids {
+		claimed, err := jobRedisClaimScript.Run(ctx, rc, []string{jobRedisQueueKey}, id, now.UnixMilli(), lockUntil).Int()
+		if err != nil {
+			return jobs, err
+		}
+		if claimed == 0 {
+			continue // claimed by another worker
+		}
+		data, err := rc.HGet(ctx, jobRedisDataKey, id).Result()
+		if err != nil {
+			return jobs, err
+		}
+		job := JobRecord{}
+		if err = json.Unmarshal([]byte(data), &job); err != nil {
+			return jobs, err
+		}
+		job.Status = NewNullString(JobStatusRunning)
+		job.Attempts = NewNullInt64(job.Attempts.Int64 + 1)
+		job.LockedBy = NewNullString(workerID)
+		job.LockedAt = NewNullDateTime(now)
+		if err = s.save(job, float64(lockUntil)); err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Complete removes the job from the queue.
+func (*jobRedisStore) Complete(job JobRecord) error {
+	rc, ctx := Cache().RedisClient, Cache().Ctx
+	_, err := rc.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZRem(ctx, jobRedisQueueKey, job.ID.String)
+		p.HDel(ctx, jobRedisDataKey, job.ID.String)
+		return nil
+	})
+	return err
+}
+
+// Retry pushes the job back to the queue to be claimed again at runAt.
+func (s *jobRedisStore) Retry(job JobRecord, runAt time.Time, err error) error {
+	job.Status = NewNullString(JobStatusPending)
+	job.RunAt = NewNullDateTime(runAt)
+	job.LastError = NewNullText(err.Error())
+	job.LockedBy = NullString{}
+	job.LockedAt = NullDateTime{}
+	return s.save(job, float64(runAt.UnixMilli()))
+}
+
+// Dead removes the job from the queue and pushes it to the dead letter list.
+func (*jobRedisStore) Dead(job JobRecord, err error) error {
+	job.Status = NewNullString(JobStatusDead)
+	job.LastError = NewNullText(err.Error())
+	job.UpdatedAt = NewNullDateTime(time.Now().UTC())
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	rc, ctx := Cache().RedisClient, Cache().Ctx
+	_, err = rc.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZRem(ctx, jobRedisQueueKey, job.ID.String)
+		p.HDel(ctx, jobRedisDataKey, job.ID.String)
+		p.LPush(ctx, jobRedisDeadKey, data)
+		return nil
+	})
+	return err
+}
+
+// Purge deletes the dead jobs which are moved to the dead letter list before the time, the done job is already removed on Complete.
+// The dead letter list is ordered from the newest, so the expired jobs are popped from the tail.
+func (*jobRedisStore) Purge(before time.Time) (int64, error) {
+	rc, ctx := Cache().RedisClient, Cache().Ctx
+	count := int64(0)
+	for {
+		data, err := rc.LIndex(ctx, jobRedisDeadKey, -1).Result()
+		if err == redis.Nil {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		job := JobRecord{}
+		if err = json.Unmarshal([]byte(data), &job); err == nil && !job.UpdatedAt.Time.Before(before) {
+			return count, nil
+		}
+		if err = rc.RPop(ctx, jobRedisDeadKey).Err(); err != nil && err != redis.Nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// save saves the job data and adds it to the queue with the score.
+func (*jobRedisStore) save(job JobRecord, score float64) error {
+	job.UpdatedAt = NewNullDateTime(time.Now().UTC())
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	rc, ctx := Cache().RedisClient, Cache().Ctx
+	_, err = rc.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, jobRedisDataKey, job.ID.String, data)
+		p.ZAdd(ctx, jobRedisQueueKey, &redis.Z{Score: score, Member: job.ID.String})
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// jobTestStore is the in memory JobStore to test the job workers.
+type jobTestStore struct {
+	retried, dead, done int
+	purgedBefore        time.Time
+}
+
+func (*jobTestStore) Push(c Ctx, job *JobRecord) error                      { return nil }
+func (*jobTestStore) Claim(workerID string, limit int) ([]JobRecord, error) { return nil, nil }
+func (s *jobTestStore) Complete(job JobRecord) error                        { s.done++; return nil }
+func (s *jobTestStore) Retry(job JobRecord, runAt time.Time, err error) error {
+	s.retried++
+	return nil
+}
+func (s *jobTestStore) Dead(job JobRecord, err error) error { s.dead++; return nil }
+func (s *jobTestStore) Purge(before time.Time) (int64, error) {
+	s.purgedBefore = before
+	return 0, nil
+}
+
+func TestJobRun(t *testing.T) {
+	store := &jobTestStore{}
+	j := &jobUtil{Store: store, RetryInterval: time.Second, handlers: map[string]func(Ctx, []byte) error{}}
+	j.handlers["test.ok"] = jobHandler(func(ctx Ctx, p map[string]string) error {
+		if p["name"] != "Kilogram" {
+			return errors.New("unexpected payload")
+		}
+		return nil
+	})
+	j.handlers["test.panic"] = func(ctx Ctx, p []byte) error { panic("oops") }
+
+	j.run(JobRecord{Name: NewNullString("test.ok"), Payload: NewNullText(`{"name":"Kilogram"}`), Attempts: NewNullInt64(1), MaxAttempts: NewNullInt64(3)})
+	j.run(JobRecord{Name: NewNullString("test.panic"), Attempts: NewNullInt64(1), MaxAttempts: NewNullInt64(3)})
+	j.run(JobRecord{Name: NewNullString("test.panic"), Attempts: NewNullInt64(3), MaxAttempts: NewNullInt64(3)})
+	j.run(JobRecord{Name: NewNullString("test.unknown"), Attempts: NewNullInt64(3), MaxAttempts: NewNullInt64(3)})
+
+	if store.done != 1 {
+		t.Errorf("Expected done [%v], got [%v]", 1, store.done)
+	}
+	if store.retried != 1 {
+		t.Errorf("Expected retried [%v], got [%v]", 1, store.retried)
+	}
+	if store.dead != 2 {
+		t.Errorf("Expected dead [%v], got [%v]", 2, store.dead)
+	}
+	if j.Backoff(3) != 4*time.Second {
+		t.Errorf("Expected backoff [%v], got [%v]", 4*time.Second, j.Backoff(3))
+	}
+}
+
+func TestJobPurgeTask(t *testing.T) {
+	store := &jobTestStore{}
+	j := &jobUtil{Store: store}
+	j.PurgeTask(Ctx{})
+	if !store.purgedBefore.IsZero() {
+		t.Errorf("Expected the jobs are kept forever when the retention is 0")
+	}
+
+	j.Retention = time.Hour
+	if err := j.PurgeTask(Ctx{}); err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	if d := time.Since(store.purgedBefore); d < time.Hour || d > time.Hour+time.Minute {
+		t.Errorf("Expected the jobs older than the retention are purged, got before [%v]", store.purgedBefore)
+	}
+}
@@ -0,0 +1,271 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// These are the content type of the partial update request body.
+const (
+	MIMEMergePatch = "application/merge-patch+json" // RFC 7396
+	MIMEJSONPatch  = "application/json-patch+json"  // RFC 6902
+)
+
+// JSONPatchOperation is the operation of the JSON Patch (RFC 6902) document.
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch is the JSON Patch (RFC 6902) document, it is used as the request body in the open api documentation.
+type JSONPatch []JSONPatchOperation
+
+// OpenAPISchemaName returns the name of the JSONPatch schema in the open api documentation.
+func (JSONPatch) OpenAPISchemaName() string {
+	return "JSONPatch"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the JSONPatch in the open api documentation.
+func (JSONPatch) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"op":    map[string]any{"type": "string", "enum": []string{"add", "remove", "replace", "move", "copy", "test"}},
+				"path":  map[string]any{"type": "string", "example": "/name"},
+				"from":  map[string]any{"type": "string"},
+				"value": map[string]any{},
+			},
+			"required": []string{"op", "path"},
+		},
+	}
+}
+
+// ApplyJSONPatch applies the JSON Patch (RFC 6902) operations to the json document and returns the patched document.
+// The operations are applied in order, and nothing is applied if any operation is failed.
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	ops := []JSONPatchOperation{}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("invalid json patch: %w", err)
+	}
+	var d any
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		var err error
+		var val any
+		if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+			if len(op.Value) == 0 {
+				return nil, fmt.Errorf("json patch operation %d: value is required", i)
+			}
+			if err = json.Unmarshal(op.Value, &val); err != nil {
+				return nil, fmt.Errorf("json patch operation %d: %w", i, err)
+			}
+		}
+		switch op.Op {
+		case "add":
+			d, err = jsonPointerSet(d, op.Path, val, true)
+		case "remove":
+			d, _, err = jsonPointerRemove(d, op.Path)
+		case "replace":
+			if _, err = jsonPointerGet(d, op.Path); err == nil {
+				d, err = jsonPointerSet(d, op.Path, val, false)
+			}
+		case "move":
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				err = fmt.Errorf("cannot move %s into its child", op.From)
+				break
+			}
+			if d, val, err = jsonPointerRemove(d, op.From); err == nil {
+				d, err = jsonPointerSet(d, op.Path, val, true)
+			}
+		case "copy":
+			if val, err = jsonPointerGet(d, op.From); err == nil {
+				d, err = jsonPointerSet(d, op.Path, jsonCopy(val), true)
+			}
+		case "test":
+			var cur any
+			if cur, err = jsonPointerGet(d, op.Path); err == nil && !reflect.DeepEqual(cur, val) {
+				err = fmt.Errorf("test is failed for %s", op.Path)
+			}
+		default:
+			err = fmt.Errorf("unknown op %q", op.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("json patch operation %d: %w", i, err)
+		}
+	}
+	return json.Marshal(d)
+}
+
+// MergePatchDiff returns the JSON Merge Patch (RFC 7396) document which changes the old document to the new document.
+func MergePatchDiff(oldDoc, newDoc []byte) ([]byte, error) {
+	var o, n any
+	if err := json.Unmarshal(oldDoc, &o); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(newDoc, &n); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatchDiff(o, n))
+}
+
+func mergePatchDiff(o, n any) any {
+	om, isOldObj := o.(map[string]any)
+	nm, isNewObj := n.(map[string]any)
+	if !isOldObj || !isNewObj {
+		return n
+	}
+	patch := map[string]any{}
+	for k, ov := range om {
+		nv, ok := nm[k]
+		if !ok {
+			patch[k] = nil
+		} else if !reflect.DeepEqual(ov, nv) {
+			patch[k] = mergePatchDiff(ov, nv)
+		}
+	}
+	for k, nv := range nm {
+		if _, ok := om[k]; !ok {
+			patch[k] = nv
+		}
+	}
+	return patch
+}
+
+// jsonPointerTokens parses the JSON Pointer (RFC 6901) to the reference tokens.
+func jsonPointerTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid json pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// jsonArrayIndex returns the index of the array for the token, "-" is the index after the last element if isAdd is true.
+func jsonArrayIndex(arr []any, token string, isAdd bool) (int, error) {
+	if token == "-" && isAdd {
+		return len(arr), nil
+	}
+	i, err := strconv.Atoi(token)
+	maxIndex := len(arr) - 1
+	if isAdd {
+		maxIndex = len(arr)
+	}
+	if err != nil || i < 0 || i > maxIndex || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return i, nil
+}
+
+func jsonPointerGet(doc any, pointer string) (any, error) {
+	tokens, err := jsonPointerTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, t := range tokens {
+		switch v := cur.(type) {
+		case map[string]any:
+			val, ok := v[t]
+			if !ok {
+				return nil, fmt.Errorf("path %s is not found", pointer)
+			}
+			cur = val
+		case []any:
+			i, err := jsonArrayIndex(v, t, false)
+			if err != nil {
+				return nil, err
+			}
+			cur = v[i]
+		default:
+			return nil, fmt.Errorf("path %s is not found", pointer)
+		}
+	}
+	return cur, nil
+}
+
+// jsonPointerSet sets the value at the pointer and returns the updated document.
+// If isAdd is true, the value is inserted to the array instead of replacing the element.
+func jsonPointerSet(doc any, pointer string, val any, isAdd bool) (any, error) {
+	tokens, err := jsonPointerTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return val, nil
+	}
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	parent, err := jsonPointerGet(doc, parentPointer)
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = val
+		return doc, nil
+	case []any:
+		i, err := jsonArrayIndex(p, last, isAdd)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdd {
+			p[i] = val
+			return doc, nil
+		}
+		p = append(p[:i], append([]any{val}, p[i:]...)...)
+		return jsonPointerSet(doc, parentPointer, p, false)
+	}
+	return nil, fmt.Errorf("path %s is not found", pointer)
+}
+
+// jsonPointerRemove removes the value at the pointer and returns the updated document and the removed value.
+func jsonPointerRemove(doc any, pointer string) (any, any, error) {
+	tokens, err := jsonPointerTokens(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	val, err := jsonPointerGet(doc, pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	parent, _ := jsonPointerGet(doc, parentPointer)
+	last := tokens[len(tokens)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		delete(p, last)
+		return doc, val, nil
+	case []any:
+		i, _ := jsonArrayIndex(p, last, false)
+		p = append(p[:i:i], p[i+1:]...)
+		doc, err = jsonPointerSet(doc, parentPointer, p, false)
+		return doc, val, err
+	}
+	return nil, nil, fmt.Errorf("path %s is not found", pointer)
+}
+
+// jsonCopy returns the deep copy of the decoded json value.
+func jsonCopy(val any) any {
+	b, _ := json.Marshal(val)
+	var c any
+	json.Unmarshal(b, &c)
+	return c
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := `{"name":"a","tags":["x","y"],"detail":{"note":"n"}}`
+	testCases := []struct {
+		patch    string
+		expected string
+		isError  bool
+	}{
+		{`[{"op":"replace","path":"/name","value":"b"}]`, `{"name":"b","tags":["x","y"],"detail":{"note":"n"}}`, false},
+		{`[{"op":"add","path":"/tags/-","value":"z"}]`, `{"name":"a","tags":["x","y","z"],"detail":{"note":"n"}}`, false},
+		{`[{"op":"add","path":"/tags/0","value":"w"}]`, `{"name":"a","tags":["w","x","y"],"detail":{"note":"n"}}`, false},
+		{`[{"op":"remove","path":"/tags/0"}]`, `{"name":"a","tags":["y"],"detail":{"note":"n"}}`, false},
+		{`[{"op":"move","from":"/detail/note","path":"/note"}]`, `{"name":"a","tags":["x","y"],"detail":{},"note":"n"}`, false},
+		{`[{"op":"copy","from":"/name","path":"/detail/name"}]`, `{"name":"a","tags":["x","y"],"detail":{"note":"n","name":"a"}}`, false},
+		{`[{"op":"test","path":"/name","value":"a"},{"op":"remove","path":"/detail"}]`, `{"name":"a","tags":["x","y"]}`, false},
+		{`[{"op":"test","path":"/name","value":"b"}]`, ``, true},
+		{`[{"op":"replace","path":"/unknown","value":"b"}]`, ``, true},
+		{`[{"op":"remove","path":"/tags/5"}]`, ``, true},
+		{`[{"op":"unknown","path":"/name"}]`, ``, true},
+	}
+	for _, tc := range testCases {
+		res, err := ApplyJSONPatch([]byte(doc), []byte(tc.patch))
+		if tc.isError {
+			if err == nil {
+				t.Errorf("Expected error for patch %v, got [%v]", tc.patch, string(res))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected no error for patch %v, got [%v]", tc.patch, err)
+			continue
+		}
+		if !isJSONEqual(string(res), tc.expected) {
+			t.Errorf("Expected patch %v result [%v], got [%v]", tc.patch, tc.expected, string(res))
+		}
+	}
+}
+
+func TestMergePatchDiff(t *testing.T) {
+	res, err := MergePatchDiff(
+		[]byte(`{"name":"a","note":"n","detail":{"x":1,"y":2},"tags":["x"]}`),
+		[]byte(`{"name":"b","detail":{"x":1,"y":3},"tags":["x","y"],"code":"c"}`),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	expected := `{"name":"b","note":null,"detail":{"y":3},"tags":["x","y"],"code":"c"}`
+	if !isJSONEqual(string(res), expected) {
+		t.Errorf("Expected [%v], got [%v]", expected, string(res))
+	}
+}
+
+func isJSONEqual(a, b string) bool {
+	var x, y any
+	json.Unmarshal([]byte(a), &x)
+	json.Unmarshal([]byte(b), &y)
+	return reflect.DeepEqual(x, y)
+}
@@ -1,13 +1,17 @@
 package app
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/jeffry-luqman/zlog"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -33,6 +37,16 @@ type loggerUtil struct {
 	json        *slog.Logger
 	textFile    *slog.Logger
 	textConsole *slog.Logger
+	fileWriter  *lumberjack.Logger
+	redactor    *logRedactor
+	buffers     []*bufio.Writer // the buffered writers of the sinks, flushed after every batch
+	queue       chan logEntry   // the bounded log queue, nil if the log is written synchronously
+	done        chan struct{}
+	mu          sync.RWMutex // guards the queue from being closed while sending to it
+	syncMu      sync.Mutex   // serializes the synchronous write when the queue is disabled or closed
+	written     int64
+	dropped     int64
+	sampledOut  int64
 }
 
 // configure sets up the logging framework
@@ -48,18 +62,15 @@ type loggerUtil struct {
 // and Kubernetes).
 //
 // The output log file will be located at LOG_FILE_FILENAME and will be rolled according to configuration set.
+//
+// The sensitive values (LOG_REDACT_FIELDS, LOG_REDACT_HEADERS and LOG_REDACT_BODY_PATHS) are masked before they are written to any sink,
+// including the telegram alert.
+//
+// The log is written asynchronously through a bounded queue of LOG_QUEUE_SIZE entries by a single worker in batches of LOG_BATCH_SIZE,
+// so logging doesn't block the request. The queue is flushed on Close.
 func (l *loggerUtil) configure() {
-	logger = &loggerUtil{}
-	mapLevel := map[string]slog.Level{
-		"debug":   slog.LevelDebug,
-		"info":    slog.LevelInfo,
-		"warning": slog.LevelWarn,
-		"error":   slog.LevelError,
-	}
-	level, ok := mapLevel[LOG_LEVEL]
-	if !ok {
-		level = slog.LevelInfo
-	}
+	logger = &loggerUtil{redactor: newLogRedactor()}
+	level := logLevel(LOG_LEVEL)
 	var jsonWriters []io.Writer
 	if LOG_CONSOLE_ENABLED {
 		if LOG_CONSOLE_WITH_JSON {
@@ -77,15 +88,18 @@ func (l *loggerUtil) configure() {
 			MaxAge:     LOG_FILE_MAX_AGE,
 			MaxBackups: LOG_FILE_MAX_BACKUPS,
 		}
+		logger.fileWriter = logFileWriter
 		if LOG_FILE_WITH_JSON {
 			jsonWriters = append(jsonWriters, logFileWriter)
 		} else {
-			logger.textFile = slog.New(slog.NewTextHandler(logFileWriter, &slog.HandlerOptions{Level: level}))
+			logger.textFile = slog.New(slog.NewTextHandler(logger.buffered(logFileWriter), &slog.HandlerOptions{Level: level}))
 		}
 	}
 	if len(jsonWriters) > 0 {
-		logger.json = slog.New(slog.NewJSONHandler(io.MultiWriter(jsonWriters...), &slog.HandlerOptions{Level: level}))
+		logger.json = slog.New(slog.NewJSONHandler(logger.buffered(io.MultiWriter(jsonWriters...)), &slog.HandlerOptions{Level: level}))
 	}
+	logger.startQueue()
+	Alerter()
 }
 
 // Attrs return common log attributes from Ctx
@@ -110,22 +124,45 @@ func (l *loggerUtil) Attrs(c Ctx, attrss ...[]any) []any {
 	return attrs
 }
 
+// Log logs a message at the level with the sensitive values masked.
 func (l *loggerUtil) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
-	if l.json != nil {
-		l.json.Log(ctx, level, msg, args...)
+	l.log(ctx, level, msg, l.redactor.Redact(args)...)
+}
+
+// log sends a message at the level to the log queue, the args must be redacted.
+// The log is also alerted if any notifier is alerted on the level, see Alerter.
+func (l *loggerUtil) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.Add(args...)
+	l.enqueue(logEntry{ctx: ctx, record: r, isAlert: Alerter().IsEnabled(level)})
+}
+
+// LogWithoutAlert logs a message at the level without alert, it is used to log the failure of the alert itself
+// and the failed request when ALERT_REQUEST_ERRORS is false.
+func (l *loggerUtil) LogWithoutAlert(level slog.Level, msg string, args ...any) {
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.Add(l.redactor.Redact(l.addBaseAttr(args...))...)
+	l.enqueue(logEntry{ctx: context.Background(), record: r})
+}
+
+// write writes the record to every sink.
+func (l *loggerUtil) write(ctx context.Context, r slog.Record) {
+	if l.json != nil && l.json.Enabled(ctx, r.Level) {
+		l.json.Handler().Handle(ctx, r.Clone())
 	}
-	if l.textFile != nil {
-		l.textFile.Log(ctx, level, msg, args...)
+	if l.textFile != nil && l.textFile.Enabled(ctx, r.Level) {
+		l.textFile.Handler().Handle(ctx, r.Clone())
 	}
-	if l.textConsole != nil {
-		var inclArgs []any
+	if l.textConsole != nil && l.textConsole.Enabled(ctx, r.Level) {
 		exclKeys := strings.Split(LOG_CONSOLE_EXCLUDED_KEYS, ",")
-		for _, a := range args {
-			if attr, ok := a.(slog.Attr); !ok || !slices.Contains(exclKeys, attr.Key) {
-				inclArgs = append(inclArgs, a)
+		cr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		r.Attrs(func(a slog.Attr) bool {
+			if !slices.Contains(exclKeys, a.Key) {
+				cr.AddAttrs(a)
 			}
-		}
-		l.textConsole.Log(ctx, level, msg, inclArgs...)
+			return true
+		})
+		l.textConsole.Handler().Handle(ctx, cr)
 	}
 }
 
@@ -147,25 +184,45 @@ func (l *loggerUtil) Warn(msg string, attrs ...any) {
 	l.Log(context.Background(), slog.LevelWarn, msg, attrs...)
 }
 
-// Error logs a message at LevelError and send alert to telegram.
+// Error logs a message at LevelError, it is alerted with the default ALERT_LEVEL.
 func (l *loggerUtil) Error(msg string, attrs ...any) {
 	attrs = l.addBaseAttr(attrs...)
-	l.sendAlert(msg, attrs)
 	l.Log(context.Background(), slog.LevelError, msg, attrs...)
 }
 
 // Fatal is equivalent to Error() followed by a call to os.Exit(1).
 func (l *loggerUtil) Fatal(msg string, attrs ...any) {
 	l.Error(msg, attrs...)
+	l.Flush()
+	Alerter().Flush()
 	os.Exit(1)
 }
 
 // Panic is equivalent to Error() followed by a call to panic().
 func (l *loggerUtil) Panic(msg string, attrs ...any) {
 	l.Error(msg, attrs...)
+	l.Flush()
+	Alerter().Flush()
 	panic(msg)
 }
 
+// Close writes the queued log entries and sends the queued alerts, then flushes and closes the log file, it is called on graceful shutdown.
+// The log after Close is written synchronously.
+func (l *loggerUtil) Close() error {
+	l.mu.Lock()
+	if l.queue != nil {
+		close(l.queue)
+		<-l.done
+		l.queue = nil
+	}
+	l.mu.Unlock()
+	Alerter().Close()
+	if l.fileWriter == nil {
+		return nil
+	}
+	return l.fileWriter.Close()
+}
+
 func (l *loggerUtil) addBaseAttr(args ...any) []any {
 	var attrs []any
 	hostname, _ := os.Hostname()
@@ -177,49 +234,58 @@ func (l *loggerUtil) addBaseAttr(args ...any) []any {
 	return append(attrs, args...)
 }
 
-// sendAlert send an alert to telegram
-func (l *loggerUtil) sendAlert(msg string, args []any) {
-	detail, trace := "", ""
-	for _, a := range args {
-		if attr, ok := a.(slog.Attr); ok {
-			if e, ok := attr.Value.Any().(error); ok {
-				msg = Error().GetError(e).OriginalMessage()
-				detailByte, _ := json.MarshalIndent(Error().GetError(e).Body(), "", "  ")
-				detail = string(detailByte)
-				traceByte, _ := json.MarshalIndent(Error().GetError(e).TraceSimple(), "", "  ")
-				trace = string(traceByte)
-			} else if attr.Key == "err_message" {
-				msg = attr.Value.String()
-			} else if attr.Key == "err_detail" {
-				detail = attr.Value.String()
-			} else if attr.Key == "err_trace" {
-				trace = attr.Value.String()
-			}
+// newAlert returns the alert of the redacted log record.
+func (l *loggerUtil) newAlert(r slog.Record) Alert {
+	a := Alert{Level: logLevelName(r.Level), Message: r.Message, Time: r.Time}
+	acceptedKey := []string{"env", "version", "method", "url", "base_url", "end_point", "referer", "ip", "hostname", "time", "debug"}
+	r.Attrs(func(attr slog.Attr) bool {
+		if e, ok := attr.Value.Any().(error); ok {
+			a.ErrorType = fmt.Sprintf("%T", e)
+			a.Message = Error().GetError(e).OriginalMessage()
+			detailByte, _ := json.MarshalIndent(Error().GetError(e).Body(), "", "  ")
+			a.Detail = l.redactor.RedactJSON(string(detailByte))
+			traceByte, _ := json.MarshalIndent(Error().GetError(e).TraceSimple(), "", "  ")
+			a.Trace = string(traceByte)
+		} else if attr.Key == "err_message" {
+			a.Message = attr.Value.String()
+		} else if attr.Key == "err_detail" {
+			a.Detail = attr.Value.String()
+		} else if attr.Key == "err_trace" {
+			a.Trace = attr.Value.String()
+		} else if attr.Key == "path" {
+			a.Path = attr.Value.String()
+		} else if attr.Key == "status" && a.ErrorType == "" {
+			a.ErrorType = "status:" + attr.Value.String()
+		} else if slices.Contains(acceptedKey, attr.Key) {
+			a.Attrs = append(a.Attrs, AlertAttr{Key: attr.Key, Value: attr.Value.String()})
 		}
-	}
+		return true
+	})
+	return a
+}
 
-	writeln := func(s *strings.Builder, args ...string) {
-		for _, arg := range args {
-			s.WriteString(arg)
-		}
-		s.WriteByte('\n')
+// logLevels maps LOG_LEVEL and ALERT_LEVEL to the slog level.
+var logLevels = map[string]slog.Level{
+	"debug":   slog.LevelDebug,
+	"info":    slog.LevelInfo,
+	"warning": slog.LevelWarn,
+	"error":   slog.LevelError,
+}
+
+// logLevel returns the slog level of the name, default is info.
+func logLevel(name string) slog.Level {
+	if level, ok := logLevels[name]; ok {
+		return level
 	}
+	return slog.LevelInfo
+}
 
-	b := &strings.Builder{}
-	writeln(b, "```")
-	writeln(b, msg)
-	acceptedKey := []string{"env", "version", "method", "url", "base_url", "end_point", "referer", "ip", "hostname", "time", "debug"}
-	for _, a := range args {
-		if attr, ok := a.(slog.Attr); ok && slices.Contains(acceptedKey, attr.Key) {
-			writeln(b, attr.Key, ": ", attr.Value.String())
+// logLevelName returns the name of the slog level, for example warning.
+func logLevelName(level slog.Level) string {
+	for name, l := range logLevels {
+		if l == level {
+			return name
 		}
 	}
-	if detail != "" && detail != "null" {
-		writeln(b, "error: ", detail)
-	}
-	if trace != "" && trace != "null" {
-		writeln(b, "trace: ", trace)
-	}
-	writeln(b, "```")
-	Telegram(b.String()).Send()
+	return strings.ToLower(level.String())
 }
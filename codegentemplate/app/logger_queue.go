@@ -0,0 +1,160 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// LogStats is the statistics of the log queue.
+type LogStats struct {
+	QueueLength   int   `json:"queue_length"`
+	QueueCapacity int   `json:"queue_capacity"`
+	Written       int64 `json:"written"`
+	Dropped       int64 `json:"dropped"`     // dropped because the queue is full with LOG_QUEUE_POLICY = drop
+	SampledOut    int64 `json:"sampled_out"` // the successful access log which is skipped by LOG_ACCESS_SAMPLE_RATE
+}
+
+// OpenAPISchemaName returns the name of the LogStats schema in the open api documentation.
+func (LogStats) OpenAPISchemaName() string {
+	return "LogStats"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the LogStats in the open api documentation.
+func (LogStats) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"queue_length":   map[string]any{"type": "integer"},
+			"queue_capacity": map[string]any{"type": "integer"},
+			"written":        map[string]any{"type": "integer"},
+			"dropped":        map[string]any{"type": "integer"},
+			"sampled_out":    map[string]any{"type": "integer"},
+		},
+	}
+}
+
+// logEntry is the log record on the log queue.
+type logEntry struct {
+	ctx     context.Context
+	record  slog.Record
+	isAlert bool          // true if the entry is sent to the alert notifiers
+	flushed chan struct{} // the flush marker, it is closed when the entries before it are written
+}
+
+// buffered returns the buffered w when the log queue is enabled, the buffer is flushed after every batch.
+func (l *loggerUtil) buffered(w io.Writer) io.Writer {
+	if LOG_QUEUE_SIZE <= 0 {
+		return w
+	}
+	b := bufio.NewWriterSize(w, 64*1024)
+	l.buffers = append(l.buffers, b)
+	return b
+}
+
+// startQueue starts the worker of the log queue, the log is written synchronously if LOG_QUEUE_SIZE is 0.
+func (l *loggerUtil) startQueue() {
+	if LOG_QUEUE_SIZE <= 0 {
+		return
+	}
+	l.queue = make(chan logEntry, LOG_QUEUE_SIZE)
+	l.done = make(chan struct{})
+	go l.runQueue()
+}
+
+// enqueue sends the entry to the log queue. When the queue is full, the entry is dropped with LOG_QUEUE_POLICY = drop,
+// or it waits until the queue has space with LOG_QUEUE_POLICY = block. The error log is never dropped.
+func (l *loggerUtil) enqueue(e logEntry) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.queue == nil {
+		l.syncMu.Lock()
+		defer l.syncMu.Unlock()
+		l.handle(e)
+		l.flushBuffers()
+		return
+	}
+	if LOG_QUEUE_POLICY == "block" || e.record.Level >= slog.LevelError || e.flushed != nil {
+		l.queue <- e
+		return
+	}
+	select {
+	case l.queue <- e:
+	default:
+		atomic.AddInt64(&l.dropped, 1)
+	}
+}
+
+// runQueue writes the entries of the log queue in batches of LOG_BATCH_SIZE until the queue is closed.
+func (l *loggerUtil) runQueue() {
+	defer close(l.done)
+	reportedAt, reported := time.Now(), int64(0)
+	for e := range l.queue {
+		l.handle(e)
+		for n := 1; n < LOG_BATCH_SIZE && len(l.queue) > 0; n++ {
+			l.handle(<-l.queue)
+		}
+		l.flushBuffers()
+		if dropped := atomic.LoadInt64(&l.dropped); dropped > reported && time.Since(reportedAt) >= time.Minute {
+			r := slog.NewRecord(time.Now(), slog.LevelWarn, "Log entries are dropped because the log queue is full", 0)
+			r.Add(slog.Int64("dropped", dropped-reported), slog.Int64("total_dropped", dropped), slog.Int("queue_size", LOG_QUEUE_SIZE))
+			l.write(context.Background(), r)
+			l.flushBuffers()
+			reportedAt, reported = time.Now(), dropped
+		}
+	}
+	l.flushBuffers()
+}
+
+// handle writes the entry to every sink and sends the alert.
+func (l *loggerUtil) handle(e logEntry) {
+	if e.flushed != nil {
+		l.flushBuffers()
+		close(e.flushed)
+		return
+	}
+	l.write(e.ctx, e.record)
+	atomic.AddInt64(&l.written, 1)
+	if e.isAlert {
+		Alerter().Send(l.newAlert(e.record))
+	}
+}
+
+func (l *loggerUtil) flushBuffers() {
+	for _, b := range l.buffers {
+		b.Flush()
+	}
+}
+
+// Flush waits until the queued log entries are written, it is called before the app exits.
+func (l *loggerUtil) Flush() {
+	flushed := make(chan struct{})
+	l.enqueue(logEntry{flushed: flushed})
+	<-flushed
+}
+
+// IsSampled returns true if the successful access log should be written, by LOG_ACCESS_SAMPLE_RATE.
+func (l *loggerUtil) IsSampled() bool {
+	if LOG_ACCESS_SAMPLE_RATE >= 1 || rand.Float64() < LOG_ACCESS_SAMPLE_RATE {
+		return true
+	}
+	atomic.AddInt64(&l.sampledOut, 1)
+	return false
+}
+
+// Stats returns the statistics of the log queue.
+func (l *loggerUtil) Stats() LogStats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return LogStats{
+		QueueLength:   len(l.queue),
+		QueueCapacity: cap(l.queue),
+		Written:       atomic.LoadInt64(&l.written),
+		Dropped:       atomic.LoadInt64(&l.dropped),
+		SampledOut:    atomic.LoadInt64(&l.sampledOut),
+	}
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogQueue(t *testing.T) {
+	size, policy := LOG_QUEUE_SIZE, LOG_QUEUE_POLICY
+	LOG_QUEUE_SIZE, LOG_QUEUE_POLICY = 2, "drop"
+	defer func() { LOG_QUEUE_SIZE, LOG_QUEUE_POLICY = size, policy }()
+
+	out := &bytes.Buffer{}
+	l := &loggerUtil{}
+	l.json = slog.New(slog.NewJSONHandler(l.buffered(out), nil))
+	l.queue = make(chan logEntry, LOG_QUEUE_SIZE)
+	l.done = make(chan struct{})
+
+	// the worker is not started yet, so the third entry is dropped
+	for _, msg := range []string{"first", "second", "third"} {
+		l.Info(msg)
+	}
+	if stats := l.Stats(); stats.Dropped != 1 || stats.QueueLength != 2 {
+		t.Errorf("Expected 1 dropped and 2 queued, got [%+v]", stats)
+	}
+	if out.Len() > 0 {
+		t.Errorf("Expected nothing is written before the worker is started, got [%v]", out.String())
+	}
+
+	go l.runQueue()
+	l.Flush()
+	if !strings.Contains(out.String(), `"msg":"first"`) || !strings.Contains(out.String(), `"msg":"second"`) || strings.Contains(out.String(), `"msg":"third"`) {
+		t.Errorf("Expected the queued entries are written, got [%v]", out.String())
+	}
+	l.Close()
+	l.Info("after close")
+	if !strings.Contains(out.String(), `"msg":"after close"`) {
+		t.Errorf("Expected the log after close is written synchronously, got [%v]", out.String())
+	}
+	if stats := l.Stats(); stats.Written != 3 {
+		t.Errorf("Expected 3 written, got [%+v]", stats)
+	}
+}
+
+// blockingNotifier blocks every alert until release is closed, then fails it.
+type blockingNotifier struct{ release chan struct{} }
+
+func (n blockingNotifier) Name() string { return "blocking" }
+
+func (n blockingNotifier) Notify(ctx context.Context, a Alert) error {
+	<-n.release
+	return errors.New("unreachable")
+}
+
+func TestLogQueueWithFullAlertQueue(t *testing.T) {
+	size, policy, window := LOG_QUEUE_SIZE, LOG_QUEUE_POLICY, ALERT_THROTTLE_WINDOW
+	LOG_QUEUE_SIZE, LOG_QUEUE_POLICY, ALERT_THROTTLE_WINDOW = 1, "block", 0
+	oldLogger, oldAlerter := logger, alrt
+	defer func() {
+		LOG_QUEUE_SIZE, LOG_QUEUE_POLICY, ALERT_THROTTLE_WINDOW = size, policy, window
+		logger, alrt = oldLogger, oldAlerter
+	}()
+
+	out := &syncBuffer{}
+	logger = &loggerUtil{}
+	logger.json = slog.New(slog.NewJSONHandler(logger.buffered(out), nil))
+	logger.startQueue()
+	alrt = &alerterUtil{}
+	n := blockingNotifier{release: make(chan struct{})}
+	alrt.AddNotifier(n, slog.LevelError)
+
+	// the first alert blocks the alert worker, the next 100 fill the alert queue and the rest are dropped,
+	// while the log queue of 1 entry is full all the time with LOG_QUEUE_POLICY = block
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 300; i++ {
+			Logger().Error("Failed to connect to main DB", slog.Int("i", i))
+		}
+		Logger().Flush()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the log worker is not blocked by the full alert queue")
+	}
+
+	close(n.release)
+	closed := make(chan struct{})
+	go func() {
+		Logger().Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the logger is closed")
+	}
+	if s := out.String(); !strings.Contains(s, "Failed to send the alert because the alert queue is full") || !strings.Contains(s, `"last_error":"blocking: unreachable"`) {
+		t.Errorf("Expected the dropped and failed alerts are reported, got [%v]", s)
+	}
+	if stats := Logger().Stats(); stats.Written < 300 {
+		t.Errorf("Expected every error log is written, got [%+v]", stats)
+	}
+}
+
+// syncBuffer is the bytes.Buffer which is safe to read while it is written by the log worker.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
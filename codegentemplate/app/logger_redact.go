@@ -0,0 +1,207 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+// Redacted is the replacement of the sensitive value on the log, the alert and the config dump.
+const Redacted = "******"
+
+// logRedactor masks the sensitive values of the log attributes before they are written to any sink.
+type logRedactor struct {
+	fields  []string   // the sensitive attribute keys and json body keys, lower case with underscore
+	headers []string   // the sensitive header names, lower case
+	paths   [][]string // the sensitive json body paths, * matches any key or array item
+}
+
+// newLogRedactor creates the logRedactor from LOG_REDACT_FIELDS, LOG_REDACT_HEADERS and LOG_REDACT_BODY_PATHS.
+func newLogRedactor() *logRedactor {
+	r := &logRedactor{}
+	for _, f := range strings.Split(LOG_REDACT_FIELDS, ",") {
+		if f = normalizeLogKey(f); f != "" {
+			r.fields = append(r.fields, f)
+		}
+	}
+	for _, h := range strings.Split(LOG_REDACT_HEADERS, ",") {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			r.headers = append(r.headers, h)
+		}
+	}
+	for _, p := range strings.Split(LOG_REDACT_BODY_PATHS, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			r.paths = append(r.paths, strings.Split(p, "."))
+		}
+	}
+	return r
+}
+
+// normalizeLogKey returns the lower case key with underscore, for example X-Api-Key become x_api_key.
+func normalizeLogKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+}
+
+// Redact returns the log arguments with the sensitive values masked.
+// The key-value pair arguments are converted to slog.Attr.
+func (r *logRedactor) Redact(args []any) []any {
+	if r == nil {
+		return args
+	}
+	res := make([]any, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch a := args[i].(type) {
+		case slog.Attr:
+			res = append(res, r.redactAttr(a, false))
+		case string:
+			if i+1 < len(args) {
+				res = append(res, r.redactAttr(slog.Any(a, args[i+1]), false))
+				i++
+			} else {
+				res = append(res, a)
+			}
+		default:
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+// redactAttr masks the attribute by its key, the header group by the header names and the json body by the keys and the paths.
+func (r *logRedactor) redactAttr(a slog.Attr, isHeader bool) slog.Attr {
+	if (isHeader && r.isSensitiveHeader(a.Key)) || r.isSensitiveField(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		attrs := v.Group()
+		res := make([]any, 0, len(attrs))
+		for _, ga := range attrs {
+			res = append(res, r.redactAttr(ga, a.Key == "header"))
+		}
+		return slog.Group(a.Key, res...)
+	case slog.KindString:
+		switch a.Key {
+		case "body_request", "body_response", "err_detail":
+			return slog.String(a.Key, r.RedactJSON(v.String()))
+		}
+	}
+	return a
+}
+
+// RedactJSON returns the json with the sensitive keys and paths masked, the non json text is returned as is.
+func (r *logRedactor) RedactJSON(s string) string {
+	if r == nil {
+		return s
+	}
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return s
+	}
+	var data any
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return s
+	}
+	isRedacted := r.redactJSONKeys(data)
+	for _, p := range r.paths {
+		if r.redactJSONPath(data, p) {
+			isRedacted = true
+		}
+	}
+	if !isRedacted {
+		return s
+	}
+	b := &bytes.Buffer{}
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if strings.Contains(trimmed, "\n") {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(data); err != nil {
+		return s
+	}
+	res := strings.TrimRight(b.String(), "\n")
+	if strings.HasSuffix(s, "\n") {
+		res += "\n"
+	}
+	return res
+}
+
+// redactJSONKeys masks the sensitive keys on any level of the json.
+func (r *logRedactor) redactJSONKeys(data any) bool {
+	isRedacted := false
+	switch d := data.(type) {
+	case map[string]any:
+		for k, v := range d {
+			if r.isSensitiveField(k) {
+				d[k], isRedacted = Redacted, true
+			} else if r.redactJSONKeys(v) {
+				isRedacted = true
+			}
+		}
+	case []any:
+		for _, v := range d {
+			if r.redactJSONKeys(v) {
+				isRedacted = true
+			}
+		}
+	}
+	return isRedacted
+}
+
+// redactJSONPath masks the value of the path, for example data.card.number or items.*.pin.
+func (r *logRedactor) redactJSONPath(data any, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	isRedacted := false
+	visit := func(set func(any), v any) {
+		if len(path) == 1 {
+			set(Redacted)
+			isRedacted = true
+		} else if r.redactJSONPath(v, path[1:]) {
+			isRedacted = true
+		}
+	}
+	switch d := data.(type) {
+	case map[string]any:
+		for k, v := range d {
+			if path[0] == "*" || path[0] == k {
+				visit(func(val any) { d[k] = val }, v)
+			}
+		}
+	case []any:
+		for i, v := range d {
+			if path[0] == "*" {
+				visit(func(val any) { d[i] = val }, v)
+			}
+		}
+	}
+	return isRedacted
+}
+
+// isSensitiveField returns true if the key is one of the sensitive fields or ends with it,
+// for example password match password, db_password and REDIS_PASSWORD.
+func (r *logRedactor) isSensitiveField(key string) bool {
+	key = normalizeLogKey(key)
+	for _, f := range r.fields {
+		if key == f || strings.HasSuffix(key, "_"+f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *logRedactor) isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, h := range r.headers {
+		if name == h {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestLogRedact(t *testing.T) {
+	r := &logRedactor{
+		fields:  []string{"password", "token"},
+		headers: []string{"authorization"},
+		paths:   [][]string{{"items", "*", "pin"}},
+	}
+	args := r.Redact([]any{
+		slog.String("REDIS_PASSWORD", "secret"),
+		slog.String("user", "admin"),
+		"access_token", "abc",
+		slog.Group("header", slog.String("Authorization", "Bearer abc"), slog.String("Accept", "*/*")),
+		slog.String("body_request", `{"username":"admin","password":"secret","items":[{"pin":1234,"qty":2}]}`),
+		slog.String("body_response", "not json password=secret"),
+	})
+	expected := map[string]string{
+		"REDIS_PASSWORD": Redacted,
+		"user":           "admin",
+		"access_token":   Redacted,
+		"header":         "[Authorization=" + Redacted + " Accept=*/*]",
+		"body_request":   `{"items":[{"pin":"` + Redacted + `","qty":2}],"password":"` + Redacted + `","username":"admin"}`,
+		"body_response":  "not json password=secret",
+	}
+	if len(args) != len(expected) {
+		t.Fatalf("Expected %v args, got [%v]", len(expected), args)
+	}
+	for _, a := range args {
+		attr, ok := a.(slog.Attr)
+		if !ok {
+			t.Fatalf("Expected slog.Attr, got [%v]", a)
+		}
+		if val := attr.Value.String(); val != expected[attr.Key] {
+			t.Errorf("Expected %s [%v], got [%v]", attr.Key, expected[attr.Key], val)
+		}
+	}
+}
@@ -17,12 +17,28 @@ type Model struct {
 	grest.Model
 }
 
+// SetOpenAPISchema returns the Open API Schema of the model, the encrypted fields are documented as a plain string.
+func (m *Model) SetOpenAPISchema(model any) map[string]any {
+	schema := m.Model.SetOpenAPISchema(model)
+	if props, ok := schema["properties"].(map[string]any); ok {
+		for _, f := range encryptedFields(model) {
+			if _, ok := props[f.Key]; ok {
+				props[f.Key] = map[string]any{"type": "string"}
+			}
+		}
+	}
+	return schema
+}
+
 type ListModel struct {
 	Count       int64 `json:"count"`
 	PageContext struct {
 		Page      int `json:"page"`
 		PerPage   int `json:"per_page"`
 		PageCount int `json:"total_pages"`
+
+		NextCursor     string `json:"next_cursor,omitempty"`
+		PreviousCursor string `json:"previous_cursor,omitempty"`
 	} `json:"page_context"`
 	Links struct {
 		First    string `json:"first"`
@@ -31,43 +47,112 @@ type ListModel struct {
 		Last     string `json:"last"`
 	} `json:"links"`
 	Data []map[string]any `json:"results"`
+
+	isEnvelopeDisabled bool
 }
 
+// QueryEnvelope is the query param to return only the results array of the list if it is set to false,
+// the pagination info is still available on the Link and X-Total-Count headers.
+const QueryEnvelope = "$envelope"
+
+// HeaderTotalCount is the response header of the total count of the list.
+const HeaderTotalCount = "X-Total-Count"
+
 func (list *ListModel) SetData(data []map[string]any, query url.Values) {
 	list.Data = data
 }
 
+// SetLink sets the links of the list based on the pagination info, and sets them to the Link header (RFC 8288)
+// with the total count to the X-Total-Count header (unless the count is skipped).
 func (list *ListModel) SetLink(c *fiber.Ctx) {
+	list.setLink(c)
+
+	links := []string{}
+	for _, l := range [][2]string{
+		{list.Links.First, "first"},
+		{list.Links.Previous, "prev"},
+		{list.Links.Next, "next"},
+		{list.Links.Last, "last"},
+	} {
+		if l[0] != "" {
+			links = append(links, "<"+l[0]+`>; rel="`+l[1]+`"`)
+		}
+	}
+	if len(links) > 0 {
+		c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+	}
+
 	q := Query().Parse(c.OriginalURL())
-	q.Set(grest.QueryLimit, strconv.Itoa(int(list.PageContext.PerPage)))
+	if q.Get(QuerySkipCount) != "true" {
+		count := list.Count
+		if list.PageContext.PerPage < 0 {
+			count = int64(len(list.Data)) // pagination is disabled
+		}
+		c.Set(HeaderTotalCount, strconv.FormatInt(count, 10))
+	}
+	list.isEnvelopeDisabled = q.Get(QueryEnvelope) == "false"
+}
 
+func (list *ListModel) setLink(c *fiber.Ctx) {
+	q := Query().Parse(c.OriginalURL())
 	path, _, _ := strings.Cut(c.OriginalURL(), "?")
+	link := func(q url.Values) string {
+		qs, _ := url.QueryUnescape(q.Encode())
+		return c.BaseURL() + path + "?" + qs
+	}
+
+	// the cursor pagination has no page number, the links are based on the cursor of the first and the last item
+	if Query().IsCursorPagination(q) {
+		q.Del(grest.QueryPage)
+		q.Set(QueryCursorLimit, strconv.Itoa(list.PageContext.PerPage))
+
+		first := cloneQuery(q)
+		first.Del(QueryCursor)
+		list.Links.First = link(first)
+
+		if list.PageContext.PreviousCursor != "" {
+			previous := cloneQuery(q)
+			previous.Set(QueryCursor, list.PageContext.PreviousCursor)
+			list.Links.Previous = link(previous)
+		}
+
+		if list.PageContext.NextCursor != "" {
+			next := cloneQuery(q)
+			next.Set(QueryCursor, list.PageContext.NextCursor)
+			list.Links.Next = link(next)
+		}
+		return
+	}
+
+	q.Set(grest.QueryLimit, strconv.Itoa(int(list.PageContext.PerPage)))
+	isSkipCount := q.Get(QuerySkipCount) == "true"
 
 	first := q
 	first.Del(grest.QueryPage)
 	first.Add(grest.QueryPage, "1")
-	firstQS, _ := url.QueryUnescape(first.Encode())
-	list.Links.First = c.BaseURL() + path + firstQS
+	list.Links.First = link(first)
 
-	if list.PageContext.Page > 1 && list.PageContext.PageCount > 1 {
+	if list.PageContext.Page > 1 && (list.PageContext.PageCount > 1 || isSkipCount) {
 		previous := q
 		previous.Set(grest.QueryPage, strconv.Itoa(int(list.PageContext.Page-1)))
-		previousQS, _ := url.QueryUnescape(previous.Encode())
-		list.Links.Previous = c.BaseURL() + path + previousQS
+		list.Links.Previous = link(previous)
 	}
 
-	if list.PageContext.Page < list.PageContext.PageCount {
+	// without the total count, the next page may exist as long as the current page is full
+	if list.PageContext.Page < list.PageContext.PageCount || (isSkipCount && list.PageContext.PerPage > 0 && len(list.Data) >= list.PageContext.PerPage) {
 		next := q
 		next.Set(grest.QueryPage, strconv.Itoa(int(list.PageContext.Page+1)))
-		nextQS, _ := url.QueryUnescape(next.Encode())
-		list.Links.Next = c.BaseURL() + path + nextQS
+		list.Links.Next = link(next)
 	}
 
+	if isSkipCount {
+		return
+	}
 	last := q
 	last.Set(grest.QueryPage, strconv.Itoa(int(list.PageContext.PageCount)))
-	lastQS, _ := url.QueryUnescape(last.Encode())
-	list.Links.Last = c.BaseURL() + path + lastQS
+	list.Links.Last = link(last)
 }
+
 func (list *ListModel) SetOpenAPISchema(m ModelInterface) map[string]any {
 	return map[string]any{
 		"type": "object",
@@ -77,6 +162,9 @@ func (list *ListModel) SetOpenAPISchema(m ModelInterface) map[string]any {
 				"page":        map[string]any{"type": "integer"},
 				"per_page":    map[string]any{"type": "integer"},
 				"total_pages": map[string]any{"type": "integer"},
+
+				"next_cursor":     map[string]any{"type": "string"},
+				"previous_cursor": map[string]any{"type": "string"},
 			}},
 			"links": map[string]any{"type": "object", "properties": map[string]any{
 				"first":    map[string]any{"type": "string"},
@@ -92,6 +180,42 @@ func (list *ListModel) SetOpenAPISchema(m ModelInterface) map[string]any {
 	}
 }
 
+// OpenAPIListHeaders returns the open api document of the pagination response headers of the list.
+func OpenAPIListHeaders() map[string]any {
+	return map[string]any{
+		fiber.HeaderLink: map[string]any{
+			"description": "The first, prev, next and last links of the list (RFC 8288)",
+			"schema":      map[string]any{"type": "string"},
+		},
+		HeaderTotalCount: map[string]any{
+			"description": "The total count of the list, it is not set if $is_skip_count is true",
+			"schema":      map[string]any{"type": "integer"},
+		},
+	}
+}
+
+// CountResponse is the response of the action which affects many data, for example to purge the trash.
+type CountResponse struct {
+	Message string `json:"message"`
+	Count   int64  `json:"count"`
+}
+
+// OpenAPISchemaName returns the name of the CountResponse schema in the open api documentation.
+func (CountResponse) OpenAPISchemaName() string {
+	return "CountResponse"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the CountResponse in the open api documentation.
+func (CountResponse) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"message": map[string]any{"type": "string"},
+			"count":   map[string]any{"type": "integer"},
+		},
+	}
+}
+
 type Setting struct {
 	Key   string `gorm:"column:key;primaryKey"`
 	Value string `gorm:"column:value"`
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestListModelSetLink(t *testing.T) {
+	f := fiber.New()
+	f.Get("/contacts", func(c *fiber.Ctx) error {
+		res := ListModel{Count: 25, Data: []map[string]any{{"id": "1"}, {"id": "2"}}}
+		res.PageContext.Page, res.PageContext.PerPage, res.PageContext.PageCount = 2, 10, 3
+		res.SetLink(c)
+		return c.JSON(Query().Return(res, true))
+	})
+
+	resp, err := f.Test(httptest.NewRequest("GET", "/contacts?$page=2&$envelope=false", nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	expected := `<http://example.com/contacts?$envelope=false&$page=1&$per_page=10>; rel="first", ` +
+		`<http://example.com/contacts?$envelope=false&$page=1&$per_page=10>; rel="prev", ` +
+		`<http://example.com/contacts?$envelope=false&$page=3&$per_page=10>; rel="next", ` +
+		`<http://example.com/contacts?$envelope=false&$page=3&$per_page=10>; rel="last"`
+	if link := resp.Header.Get(fiber.HeaderLink); link != expected {
+		t.Errorf("Expected Link [%v], got [%v]", expected, link)
+	}
+	if count := resp.Header.Get(HeaderTotalCount); count != "25" {
+		t.Errorf("Expected X-Total-Count [25], got [%v]", count)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	data := []map[string]any{}
+	if err = json.Unmarshal(body, &data); err != nil || len(data) != 2 {
+		t.Errorf("Expected results array, got [%v]", string(body))
+	}
+
+	resp, _ = f.Test(httptest.NewRequest("GET", "/contacts?$page=2&$is_skip_count=true", nil))
+	if count := resp.Header.Get(HeaderTotalCount); count != "" {
+		t.Errorf("Expected no X-Total-Count, got [%v]", count)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	res := map[string]any{}
+	if err = json.Unmarshal(body, &res); err != nil || res["results"] == nil {
+		t.Errorf("Expected list envelope, got [%v]", string(body))
+	}
+}
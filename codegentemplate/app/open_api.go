@@ -41,6 +41,26 @@ Example :
 GET /contacts?$page=3&$per_page=10
 ` + "`" + `` + "`" + `` + "`" + `
 
+On the large data, use the following query parameters to skip the total count or to paginate with the cursor (keyset) instead of the page number :
+
+* ` + "`" + `$is_skip_count` + "`" + `: used to skip the total count, the ` + "`" + `count` + "`" + `, ` + "`" + `total_pages` + "`" + ` and the last link are empty, default = false.
+* ` + "`" + `$limit` + "`" + `: used to paginate with the cursor and to specify the number of items to retrieve, default = 10.
+* ` + "`" + `$cursor` + "`" + `: used to specify the cursor from the ` + "`" + `next` + "`" + ` or ` + "`" + `previous` + "`" + ` link, the cursor is only valid with the same ` + "`" + `$sort` + "`" + `.
+Example :
+` + "`" + `` + "`" + `` + "`" + `
+GET /contacts?$limit=10&$sort=-created_at&$is_skip_count=true
+` + "`" + `` + "`" + `` + "`" + `
+
+The pagination links are also available on the ` + "`" + `Link` + "`" + ` header ([RFC 8288](https://www.rfc-editor.org/rfc/rfc8288)) and the total count on the ` + "`" + `X-Total-Count` + "`" + ` header,
+use ` + "`" + `$envelope=false` + "`" + ` to return only the results array without the pagination info on the body.
+Example :
+` + "`" + `` + "`" + `` + "`" + `
+GET /contacts?$page=2&$envelope=false
+
+Link: <https://api.example.com/contacts?$page=1&$per_page=10&$envelope=false>; rel="first", <https://api.example.com/contacts?$page=3&$per_page=10&$envelope=false>; rel="next", ...
+X-Total-Count: 125
+` + "`" + `` + "`" + `` + "`" + `
+
 ### Sorting
 
 You can use the ` + "`" + `$sort` + "`" + ` query parameter for sorting.
@@ -163,6 +183,18 @@ Example :
 		},
 		"explode": true,
 	}
+	param["queryParam.Format"] = map[string]any{
+		"in":          "query",
+		"name":        QueryFormat,
+		"description": "Download all the filtered data as a file, it overrides the Accept header",
+		"schema":      map[string]any{"type": "string", "enum": []string{ExportFormatCSV, ExportFormatXLSX, ExportFormatNDJSON}},
+	}
+	param["queryParam.Envelope"] = map[string]any{
+		"in":          "query",
+		"name":        QueryEnvelope,
+		"description": "Set to false to return only the results array, the pagination info is available on the Link and X-Total-Count headers",
+		"schema":      map[string]any{"type": "boolean", "default": true},
+	}
 	param["headerParam.Accept-Language"] = map[string]any{
 		"in":   "header",
 		"name": "Accept-Language",
@@ -172,6 +204,25 @@ Example :
 			"enum":    []string{"en-US", "en", "id-ID", "id"},
 		},
 	}
+	param["headerParam.If-Match"] = map[string]any{
+		"in":          "header",
+		"name":        "If-Match",
+		"description": "The ETag of the data from the previous response, the request is rejected with 412 if the data has been changed since then",
+		"schema":      map[string]any{"type": "string"},
+		"required":    IS_REQUIRE_IF_MATCH,
+	}
+	param["headerParam.If-None-Match"] = map[string]any{
+		"in":          "header",
+		"name":        "If-None-Match",
+		"description": "The ETag of the data from the previous response, it responds 304 without body if the data has not been changed",
+		"schema":      map[string]any{"type": "string"},
+	}
+	param["headerParam.Idempotency-Key"] = map[string]any{
+		"in":          "header",
+		"name":        "Idempotency-Key",
+		"description": "The unique key of the request generated by the client (e.g. uuid), the retry with the same key replays the first response instead of creating the duplicate data",
+		"schema":      map[string]any{"type": "string", "maxLength": 255},
+	}
 	o.Components["parameters"] = param
 	o.Components["securitySchemes"] = map[string]any{
 		"bearerTokenAuth": map[string]any{
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule returns a pointer to the scheduleUtil instance (sch).
+// If sch is not initialized, it creates a new scheduleUtil instance, configures it, and assigns it to sch.
+// It ensures that only one instance of scheduleUtil is created and reused.
+func Schedule() *scheduleUtil {
+	if sch == nil {
+		sch = &scheduleUtil{}
+		sch.configure()
+	}
+	return sch
+}
+
+// sch is a pointer to a scheduleUtil instance.
+// It is used to store and access the singleton instance of scheduleUtil.
+var sch *scheduleUtil
+
+// scheduleUtil represents a distributed-safe task scheduler.
+// Every instance of the app runs the scheduler, each run of the task is guarded by the distributed lock,
+// so the task is only run by one instance, and the run is saved to the run history.
+type scheduleUtil struct {
+	LockTimeout time.Duration
+	LockGrace   time.Duration
+	cron        *cron.Cron
+	tasks       map[string]*scheduledTask
+	instanceID  string
+	mu          *sync.Mutex
+}
+
+// scheduledTask is the registered task of the scheduler.
+type scheduledTask struct {
+	name    string
+	spec    string
+	entryID cron.EntryID
+	fn      func(Ctx) error
+}
+
+// configure configures the schedule utility instance.
+func (s *scheduleUtil) configure() {
+	s.LockTimeout = SCHEDULER_LOCK_TIMEOUT
+	s.LockGrace = SCHEDULER_LOCK_GRACE
+	s.cron = cron.New()
+	s.tasks = map[string]*scheduledTask{}
+	hostname, _ := os.Hostname()
+	s.instanceID = hostname + "-" + Crypto().NewToken()[:8]
+	s.mu = &sync.Mutex{}
+}
+
+// Add registers the named task to run on the cron spec, for example :
+//
+//	app.Schedule().Add("invoices.send_reminder", "CRON_TZ=Asia/Jakarta 5 0 * * *", invoice.SendReminder)
+//
+// The name must be unique, it is used as the lock key and to trigger the task manually.
+func (s *scheduleUtil) Add(name, spec string, fn func(ctx Ctx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[name]; ok {
+		return fmt.Errorf("scheduled task %s is already registered", name)
+	}
+	t := &scheduledTask{name: name, spec: spec, fn: fn}
+	id, err := s.cron.AddFunc(spec, func() {
+		s.run(t, ScheduleTriggerCron, time.Now().UTC().Truncate(time.Second))
+	})
+	if err != nil {
+		return err
+	}
+	t.entryID = id
+	s.tasks[name] = t
+	return nil
+}
+
+// Start starts the scheduler on the background.
+func (s *scheduleUtil) Start() {
+	s.cron.Start()
+}
+
+// Stop stops the scheduler and waits for running tasks to complete or until ctx is done.
+func (s *scheduleUtil) Stop(ctx context.Context) {
+	select {
+	case <-s.cron.Stop().Done():
+	case <-ctx.Done():
+	}
+}
+
+// Tasks returns the registered tasks with the next run and the last run from the run history.
+func (s *scheduleUtil) Tasks() []ScheduledTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := []ScheduledTask{}
+	for _, t := range s.tasks {
+		st := ScheduledTask{Name: NewNullString(t.name), Spec: NewNullString(t.spec)}
+		if e := s.cron.Entry(t.entryID); !e.Next.IsZero() {
+			st.NextRunAt = NewNullDateTime(e.Next.UTC())
+		}
+		if tx, err := DB().Conn("main"); err == nil {
+			last := ScheduleRun{}
+			if tx.Where("name = ?", t.name).Order("started_at desc").Take(&last).Error == nil {
+				st.LastRun = &last
+			}
+		}
+		tasks = append(tasks, st)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name.String < tasks[j].Name.String })
+	return tasks
+}
+
+// Trigger runs the task manually on the background, it returns the run history which is saved before the task is run.
+// It returns error if the task is not found or the task is being run by another instance.
+func (s *scheduleUtil) Trigger(c Ctx, name string) (ScheduleRun, error) {
+	s.mu.Lock()
+	t, ok := s.tasks[name]
+	s.mu.Unlock()
+	if !ok {
+		return ScheduleRun{}, c.NotFoundError(nil, ScheduledTask{}.EndPoint(), "name", name)
+	}
+	now := time.Now().UTC()
+	if !s.lock(name, now) {
+		return ScheduleRun{}, Error().New(http.StatusConflict, "scheduled task "+name+" is running")
+	}
+	r, err := s.start(t, ScheduleTriggerManual, now)
+	if err != nil {
+		s.unlock(name, now)
+		return r, Error().New(http.StatusInternalServerError, err.Error())
+	}
+	Server().Go(c, "Failed to run scheduled task", func() {
+		defer s.unlock(name, now)
+		s.finish(&r, s.call(t))
+	})
+	return r, nil
+}
+
+// run runs the task if the lock of the schedule is acquired by this instance.
+// The lock of the schedule (the task name and the scheduled time) is kept until the scheduled time + LockGrace,
+// so the other instance with slightly different clock doesn't run the same schedule, without blocking the next schedule.
+// The task is also locked while it is running, so the schedule is skipped if the previous run is still running.
+func (s *scheduleUtil) run(t *scheduledTask, trigger string, scheduledAt time.Time) {
+	runKey := t.name + "@" + scheduledAt.Format(time.RFC3339)
+	if !s.lock(runKey, scheduledAt) {
+		Logger().Debug("Scheduled task is run by another instance", slog.String("task", t.name))
+		return
+	}
+	defer s.unlock(runKey, scheduledAt.Add(s.LockGrace))
+	now := time.Now().UTC()
+	if !s.lock(t.name, now) {
+		Logger().Warn("Scheduled task is skipped, the previous run is still running", slog.String("task", t.name), slog.Time("scheduled_at", scheduledAt))
+		return
+	}
+	defer s.unlock(t.name, now)
+	r, err := s.start(t, trigger, scheduledAt)
+	if err != nil {
+		Logger().Error("Failed to save scheduled task run", slog.String("task", t.name), slog.Any("err", err))
+	}
+	s.finish(&r, s.call(t))
+}
+
+// start saves the running task to the run history.
+func (s *scheduleUtil) start(t *scheduledTask, trigger string, scheduledAt time.Time) (ScheduleRun, error) {
+	r := ScheduleRun{}
+	r.ID = NewNullUUID()
+	r.Name = NewNullString(t.name)
+	r.Trigger = NewNullString(trigger)
+	r.InstanceID = NewNullString(s.instanceID)
+	r.Status = NewNullString(ScheduleRunStatusRunning)
+	r.ScheduledAt = NewNullDateTime(scheduledAt)
+	r.StartedAt = NewNullDateTime(time.Now().UTC())
+	tx, err := DB().Conn("main")
+	if err != nil {
+		return r, err
+	}
+	return r, tx.Create(&r).Error
+}
+
+// finish saves the result of the task to the run history.
+func (s *scheduleUtil) finish(r *ScheduleRun, err error) {
+	r.FinishedAt = NewNullDateTime(time.Now().UTC())
+	r.Duration = NewNullInt64(r.FinishedAt.Time.Sub(r.StartedAt.Time).Milliseconds())
+	r.Status = NewNullString(ScheduleRunStatusSuccess)
+	if err != nil {
+		r.Status = NewNullString(ScheduleRunStatusFailed)
+		r.Error = NewNullText(err.Error())
+		Logger().Error("Scheduled task is failed", slog.String("task", r.Name.String), slog.String("run_id", r.ID.String), slog.Any("err", err))
+	}
+	tx, err := DB().Conn("main")
+	if err == nil {
+		err = tx.Model(&ScheduleRun{}).Where("id = ?", r.ID).Updates(map[string]any{
+			"status":      r.Status,
+			"error":       r.Error,
+			"finished_at": r.FinishedAt,
+			"duration":    r.Duration,
+		}).Error
+	}
+	if err != nil {
+		Logger().Error("Failed to save scheduled task run", slog.String("task", r.Name.String), slog.Any("err", err))
+	}
+}
+
+// call calls the task with async Ctx, it catch and recover from panics as an error.
+func (s *scheduleUtil) call(t *scheduledTask) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return t.fn(Ctx{Lang: "en", IsAsync: true, Action: Action{Method: "CRON", Path: t.name}})
+}
+
+// lock acquires the distributed lock of the key until now + LockTimeout.
+// It uses redis if the cache is connected to redis, otherwise it uses the schedule_locks table on the main db.
+func (s *scheduleUtil) lock(key string, now time.Time) bool {
+	until := now.Add(s.LockTimeout)
+	if Cache().IsUseRedis {
+		ok, err := Cache().RedisClient.SetNX(Cache().Ctx, "schedule_locks:"+key, s.instanceID, time.Until(until)).Result()
+		if err != nil {
+			Logger().Error("Failed to lock scheduled task", slog.String("key", key), slog.Any("err", err))
+		}
+		return ok
+	}
+	tx, err := DB().Conn("main")
+	if err != nil {
+		Logger().Error("Failed to lock scheduled task", slog.String("key", key), slog.Any("err", err))
+		return false
+	}
+	res := tx.Model(&ScheduleLock{}).Where("name = ?", key).Where("locked_until <= ?", now).Updates(map[string]any{
+		"locked_by":    s.instanceID,
+		"locked_until": until,
+	})
+	if res.Error == nil && res.RowsAffected > 0 {
+		return true
+	}
+	// the insert is failed by the primary key if the lock is exists (locked by another instance)
+	return tx.Create(&ScheduleLock{
+		Name:        NewNullString(key),
+		LockedBy:    NewNullString(s.instanceID),
+		LockedUntil: NewNullDateTime(until),
+	}).Error == nil
+}
+
+// unlockScript keeps the redis lock until the ttl (in milliseconds) or deletes it if the ttl is not positive,
+// only if the lock is still owned by this instance.
+var unlockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[2]) > 0 then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return redis.call("del", KEYS[1])
+`)
+
+// unlock keeps the lock of the key until the specified time, the lock is released if until is already passed.
+// The lock is only changed if it is still owned by this instance, it may be acquired by another instance after LockTimeout.
+func (s *scheduleUtil) unlock(key string, until time.Time) {
+	var err error
+	if Cache().IsUseRedis {
+		ttl := time.Until(until).Milliseconds()
+		err = unlockScript.Run(Cache().Ctx, Cache().RedisClient, []string{"schedule_locks:" + key}, s.instanceID, ttl).Err()
+		if err == redis.Nil {
+			err = nil
+		}
+	} else {
+		tx, e := DB().Conn("main")
+		if e == nil {
+			q := tx.Model(&ScheduleLock{}).Where("name = ?", key).Where("locked_by = ?", s.instanceID)
+			if until.After(time.Now()) {
+				e = q.Update("locked_until", until).Error
+			} else {
+				e = q.Delete(&ScheduleLock{}).Error
+			}
+		}
+		if e == nil {
+			// the lock of the schedule is never acquired again after its grace, delete the expired locks
+			e = tx.Where("locked_until < ?", time.Now().UTC().Add(-s.LockTimeout)).Delete(&ScheduleLock{}).Error
+		}
+		err = e
+	}
+	if err != nil {
+		Logger().Error("Failed to unlock scheduled task", slog.String("key", key), slog.Any("err", err))
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+// These are the trigger of the scheduled task run.
+const (
+	ScheduleTriggerCron   = "cron"
+	ScheduleTriggerManual = "manual"
+)
+
+// These are the status of the scheduled task run.
+const (
+	ScheduleRunStatusRunning = "running"
+	ScheduleRunStatusSuccess = "success"
+	ScheduleRunStatusFailed  = "failed"
+)
+
+// ScheduledTask is the registered task of the scheduler.
+type ScheduledTask struct {
+	Name      NullString   `json:"name"`
+	Spec      NullString   `json:"spec"`
+	NextRunAt NullDateTime `json:"next_run_at"`
+	LastRun   *ScheduleRun `json:"last_run"`
+}
+
+// EndPoint returns the ScheduledTask end point, it used for cache key, etc.
+func (ScheduledTask) EndPoint() string {
+	return "schedules"
+}
+
+// OpenAPISchemaName returns the name of the ScheduledTask schema in the open api documentation.
+func (ScheduledTask) OpenAPISchemaName() string {
+	return "ScheduledTask"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ScheduledTask in the open api documentation.
+func (ScheduledTask) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":        map[string]any{"type": "string"},
+			"spec":        map[string]any{"type": "string"},
+			"next_run_at": map[string]any{"type": "string", "format": "date-time"},
+			"last_run":    map[string]any{"$ref": "#/components/schemas/ScheduleRun"},
+		},
+	}
+}
+
+type ScheduledTaskList struct {
+	Data []ScheduledTask `json:"results"`
+}
+
+// OpenAPISchemaName returns the name of the ScheduledTaskList schema in the open api documentation.
+func (ScheduledTaskList) OpenAPISchemaName() string {
+	return "ScheduledTaskList"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ScheduledTaskList in the open api documentation.
+func (ScheduledTaskList) GetOpenAPISchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"results": map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/ScheduledTask"}},
+		},
+	}
+}
+
+// ScheduleRun is the run history of the scheduled task.
+type ScheduleRun struct {
+	Model
+	ID          NullUUID     `json:"id"           db:"m.id"           gorm:"column:id;primaryKey"`
+	Name        NullString   `json:"name"         db:"m.name"         gorm:"column:name;index"`
+	Trigger     NullString   `json:"trigger"      db:"m.trigger"      gorm:"column:trigger"`
+	InstanceID  NullString   `json:"instance_id"  db:"m.instance_id"  gorm:"column:instance_id"`
+	Status      NullString   `json:"status"       db:"m.status"       gorm:"column:status"`
+	Error       NullText     `json:"error"        db:"m.error"        gorm:"column:error"`
+	ScheduledAt NullDateTime `json:"scheduled_at" db:"m.scheduled_at" gorm:"column:scheduled_at"`
+	StartedAt   NullDateTime `json:"started_at"   db:"m.started_at"   gorm:"column:started_at"`
+	FinishedAt  NullDateTime `json:"finished_at"  db:"m.finished_at"  gorm:"column:finished_at"`
+	Duration    NullInt64    `json:"duration"     db:"m.duration"     gorm:"column:duration"` // in milliseconds
+}
+
+// EndPoint returns the ScheduleRun end point, it used for cache key, etc.
+func (ScheduleRun) EndPoint() string {
+	return "schedule_runs"
+}
+
+// TableVersion returns the versions of the ScheduleRun table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (ScheduleRun) TableVersion() string {
+	return "2026-10-19_13.00"
+}
+
+// TableName returns the name of the ScheduleRun table in the database.
+func (ScheduleRun) TableName() string {
+	return "schedule_runs"
+}
+
+// TableAliasName returns the table alias name of the ScheduleRun table, used for querying.
+func (ScheduleRun) TableAliasName() string {
+	return "m"
+}
+
+// GetRelations returns the relations of the ScheduleRun data in the database, used for querying.
+func (m *ScheduleRun) GetRelations() map[string]map[string]any {
+	return m.Relations
+}
+
+// GetFilters returns the filter of the ScheduleRun data in the database, used for querying.
+func (m *ScheduleRun) GetFilters() []map[string]any {
+	return m.Filters
+}
+
+// GetSorts returns the default sort of the ScheduleRun data in the database, used for querying.
+func (m *ScheduleRun) GetSorts() []map[string]any {
+	m.AddSort(map[string]any{"column": "m.started_at", "direction": "desc"})
+	return m.Sorts
+}
+
+// GetFields returns list of the field of the ScheduleRun data in the database, used for querying.
+func (m *ScheduleRun) GetFields() map[string]map[string]any {
+	m.SetFields(m)
+	return m.Fields
+}
+
+// GetSchema returns the ScheduleRun schema, used for querying.
+func (m *ScheduleRun) GetSchema() map[string]any {
+	return m.SetSchema(m)
+}
+
+// OpenAPISchemaName returns the name of the ScheduleRun schema in the open api documentation.
+func (ScheduleRun) OpenAPISchemaName() string {
+	return "ScheduleRun"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ScheduleRun in the open api documentation.
+func (m *ScheduleRun) GetOpenAPISchema() map[string]any {
+	return m.SetOpenAPISchema(m)
+}
+
+type ScheduleRunList struct {
+	ListModel
+	Data []ScheduleRun `json:"results"`
+}
+
+// OpenAPISchemaName returns the name of the ScheduleRunList schema in the open api documentation.
+func (ScheduleRunList) OpenAPISchemaName() string {
+	return "ScheduleRunList"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the ScheduleRunList in the open api documentation.
+func (p *ScheduleRunList) GetOpenAPISchema() map[string]any {
+	return p.SetOpenAPISchema(&ScheduleRun{})
+}
+
+// ScheduleLock is the distributed lock of the scheduled task, used if the cache is not connected to redis.
+type ScheduleLock struct {
+	Name        NullString   `json:"name"         gorm:"column:name;primaryKey"`
+	LockedBy    NullString   `json:"locked_by"    gorm:"column:locked_by"`
+	LockedUntil NullDateTime `json:"locked_until" gorm:"column:locked_until"`
+}
+
+// TableVersion returns the versions of the ScheduleLock table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (ScheduleLock) TableVersion() string {
+	return "2026-10-19_13.00"
+}
+
+// TableName returns the name of the ScheduleLock table in the database.
+func (ScheduleLock) TableName() string {
+	return "schedule_locks"
+}
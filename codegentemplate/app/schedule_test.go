@@ -0,0 +1,26 @@
+package app
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestScheduleAddAndCall(t *testing.T) {
+	s := &scheduleUtil{cron: cron.New(), tasks: map[string]*scheduledTask{}, mu: &sync.Mutex{}}
+	if err := s.Add("test.panic", "* * * * *", func(ctx Ctx) error { panic("oops") }); err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+	if err := s.Add("test.panic", "* * * * *", func(ctx Ctx) error { return nil }); err == nil {
+		t.Errorf("Expected error on duplicate task name")
+	}
+	if err := s.Add("test.invalid", "invalid spec", func(ctx Ctx) error { return nil }); err == nil {
+		t.Errorf("Expected error on invalid spec")
+	}
+	err := s.call(s.tasks["test.panic"])
+	if err == nil || !strings.HasPrefix(err.Error(), "panic: oops") {
+		t.Errorf("Expected panic error, got [%v]", err)
+	}
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"io/fs"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"sync"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/filesystem"
@@ -31,10 +33,12 @@ type serverUtil struct {
 	KeyFile               string
 	DisableStartupMessage bool
 	Fiber                 *fiber.App
+	bg                    *sync.WaitGroup // tracks background process started with Go
 }
 
 func (s *serverUtil) configure() {
 	s.Addr = ":" + APP_PORT
+	s.bg = &sync.WaitGroup{}
 	s.Fiber = fiber.New(fiber.Config{
 		ErrorHandler:          s.Error,
 		ReadBufferSize:        16384,
@@ -166,6 +170,18 @@ func (serverUtil) RecoverAsync(ctx Ctx, msg string) {
 	}
 }
 
+// Go runs fn on a new goroutine tracked by the server, so Shutdown can wait for it to finish.
+// Use it instead of a bare go statement for background process (hook, log, etc).
+// It catch and recover from panics with RecoverAsync.
+func (s *serverUtil) Go(ctx Ctx, msg string, fn func()) {
+	s.bg.Add(1)
+	go func() {
+		defer s.bg.Done()
+		defer s.RecoverAsync(ctx, msg)
+		fn()
+	}()
+}
+
 func (s *serverUtil) Test(req *http.Request, msTimeout ...int) (*http.Response, error) {
 	return s.Fiber.Test(req, msTimeout...)
 }
@@ -180,3 +196,23 @@ func (s *serverUtil) Start() error {
 	}
 	return s.Fiber.Listen(s.Addr)
 }
+
+// Shutdown gracefully shuts down the server.
+// It stops accepting new connections, waits for in-flight requests and then waits for background process started with Go.
+// It returns ctx.Err() if the ctx is done before all of them are finished.
+func (s *serverUtil) Shutdown(ctx context.Context) error {
+	err := s.Fiber.ShutdownWithContext(ctx)
+	done := make(chan struct{})
+	go func() {
+		s.bg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
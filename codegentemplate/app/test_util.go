@@ -62,6 +62,25 @@ func (t *testUtil) configure() {
 			panic(err)
 		}
 	}
+
+	// the async ctx and the job store use the main connection, use the test db for them too.
+	if _, err = DB().Conn("main"); err != nil {
+		DB().RegisterConn("main", t.Tx)
+	}
+}
+
+// RunJobs claims and runs the due background jobs until there is no due job,
+// so the test can assert the result of the job, for example the history saved by the hook job.
+func (*testUtil) RunJobs() {
+	for {
+		jobs, err := Job().Store.Claim("test", 10)
+		if err != nil || len(jobs) == 0 {
+			return
+		}
+		for _, job := range jobs {
+			Job().run(job)
+		}
+	}
 }
 
 func (t *testUtil) NewCtx(aclKeys []string) fiber.Handler {
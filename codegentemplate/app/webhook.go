@@ -0,0 +1,399 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook returns a pointer to the webhookUtil instance (wh).
+// If wh is not initialized, it creates a new webhookUtil instance, configures it, and assigns it to wh.
+// It ensures that only one instance of webhookUtil is created and reused.
+func Webhook() *webhookUtil {
+	if wh == nil {
+		wh = &webhookUtil{}
+		wh.configure()
+	}
+	return wh
+}
+
+// wh is a pointer to a webhookUtil instance.
+// It is used to store and access the singleton instance of webhookUtil.
+var wh *webhookUtil
+
+// webhookUtil represents a utility to send signed webhook to the subscribers.
+type webhookUtil struct {
+	MaxAttempts int // the max attempts of the "webhook.deliver" job, the retry and the dead letter are handled by the job queue
+	Client      *http.Client
+}
+
+// configure configures the webhook utility instance.
+func (w *webhookUtil) configure() {
+	w.MaxAttempts = WEBHOOK_MAX_ATTEMPTS
+	w.Client = &http.Client{Timeout: WEBHOOK_TIMEOUT}
+}
+
+// Dispatch sends the event to every active webhook subscription matching the event, for example `end_point.created`.
+// Each delivery is saved to the delivery log and sent on the background job, using the db transaction of the ctx.
+func (w *webhookUtil) Dispatch(c Ctx, event string, data json.RawMessage) error {
+	if len(data) == 0 {
+		data = json.RawMessage("null")
+	}
+	tx, err := c.DB()
+	if err != nil {
+		return err
+	}
+	subscriptions := []WebhookSubscription{}
+	err = tx.Where("is_active = ?", true).Where("deleted_at is null").Find(&subscriptions).Error
+	if err != nil {
+		return fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	for _, s := range subscriptions {
+		if !s.IsMatch(event) {
+			continue
+		}
+		d := WebhookDelivery{}
+		d.ID = NewNullUUID()
+		d.WebhookID = s.ID
+		d.Event = NewNullString(event)
+		payload, _ := json.Marshal(map[string]any{
+			"id":         d.ID.String,
+			"event":      event,
+			"created_at": time.Now().UTC().Format(time.RFC3339),
+			"data":       data,
+		})
+		d.Payload = NewNullText(string(payload))
+		d.CreatedAt = NewNullDateTime(time.Now().UTC())
+		if err = tx.Create(&d).Error; err != nil {
+			return fmt.Errorf("failed to save webhook delivery: %w", err)
+		}
+		if err = Job().Enqueue(c, "webhook.deliver", d.ID.String, JobOption{MaxAttempts: w.MaxAttempts}); err != nil {
+			return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// deliverJob is the background job handler to deliver the webhook delivery and save the attempt to the delivery log.
+// It returns error if the delivery is failed, so the job is retried with exponential backoff and moved to the dead letter after MaxAttempts.
+func (w *webhookUtil) deliverJob(c Ctx, deliveryID string) error {
+	tx, err := DB().Conn("main")
+	if err != nil {
+		return err
+	}
+	d := WebhookDelivery{}
+	if err = tx.Where("id = ?", deliveryID).Take(&d).Error; err != nil {
+		return err
+	}
+	s := WebhookSubscription{}
+	if err = tx.Where("id = ?", d.WebhookID).Take(&s).Error; err != nil {
+		return err
+	}
+	if d.DeliveredAt.Valid {
+		return nil // already delivered by the previous attempt
+	}
+	err = w.Deliver(&d, s)
+	w.save(c, &d)
+	return err
+}
+
+// Event returns the webhook event name of the entity based on http method, for example `end_point.created`.
+func (w *webhookUtil) Event(entity, method string) string {
+	switch method {
+	case http.MethodPost:
+		return entity + ".created"
+	case http.MethodDelete:
+		return entity + ".deleted"
+	case "RESTORE":
+		return entity + ".restored"
+	}
+	return entity + ".updated"
+}
+
+// Redeliver sends the payload of the specified delivery of the webhook subscription again as a new delivery.
+func (w *webhookUtil) Redeliver(c Ctx, webhookID, deliveryID string) (WebhookDelivery, error) {
+	d := WebhookDelivery{}
+	tx, err := c.DB()
+	if err != nil {
+		return d, Error().New(http.StatusInternalServerError, err.Error())
+	}
+	old := WebhookDelivery{}
+	if err = tx.Where("id = ?", deliveryID).Where("webhook_id = ?", webhookID).Take(&old).Error; err != nil {
+		return d, c.NotFoundError(err, WebhookDelivery{}.EndPoint(), "id", deliveryID)
+	}
+	s := WebhookSubscription{}
+	if err = tx.Where("id = ?", old.WebhookID).Where("deleted_at is null").Take(&s).Error; err != nil {
+		return d, c.NotFoundError(err, WebhookSubscription{}.EndPoint(), "id", old.WebhookID.String)
+	}
+	d.ID = NewNullUUID()
+	d.WebhookID = old.WebhookID
+	d.Event = old.Event
+	d.Payload = old.Payload
+	d.RedeliveryOf = old.ID
+	d.CreatedAt = NewNullDateTime(time.Now().UTC())
+	if err = tx.Create(&d).Error; err != nil {
+		return d, Error().New(http.StatusInternalServerError, err.Error())
+	}
+	if err = Job().Enqueue(c, "webhook.deliver", d.ID.String, JobOption{MaxAttempts: w.MaxAttempts}); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+// Deliver sends the delivery to the subscription url once and sets the result of the attempt to the delivery.
+// It returns error if the request is failed or the http status code is not 2xx.
+func (w *webhookUtil) Deliver(d *WebhookDelivery, s WebhookSubscription) error {
+	statusCode, body, err := w.Send(s.URL.String, s.Secret.String, d.ID.String, d.Event.String, []byte(d.Payload.String))
+	d.Attempts = NewNullInt64(d.Attempts.Int64 + 1)
+	d.StatusCode = NewNullInt64(int64(statusCode))
+	d.ResponseBody = NewNullText(body)
+	d.Error = NullText{}
+	if err == nil && (statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices) {
+		err = errors.New("unexpected response status " + strconv.Itoa(statusCode))
+	}
+	if err != nil {
+		d.Error = NewNullText(err.Error())
+		return err
+	}
+	d.DeliveredAt = NewNullDateTime(time.Now().UTC())
+	return nil
+}
+
+// Send sends a single signed POST request of the payload to the url.
+// It returns the response status code and body (truncated to 64KB).
+//
+// The receiver can verify the request by computing Sign(secret, X-Webhook-Timestamp, body)
+// and comparing it to the X-Webhook-Signature header.
+func (w *webhookUtil) Send(url, secret, deliveryID, event string, payload []byte) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "My App API Webhook")
+	req.Header.Set("X-Webhook-ID", deliveryID)
+	req.Header.Set("X-Webhook-Event", event)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", "sha256="+w.Sign(secret, timestamp, payload))
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	return res.StatusCode, string(body), err
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of "{timestamp}.{payload}" using the secret.
+func (w *webhookUtil) Sign(secret string, timestamp int64, payload []byte) string {
+	return Crypto().HMAC(secret, strconv.FormatInt(timestamp, 10)+"."+string(payload))
+}
+
+// save saves the delivery result to the delivery log.
+func (w *webhookUtil) save(c Ctx, d *WebhookDelivery) {
+	tx, err := DB().Conn("main")
+	if err == nil {
+		err = tx.Model(d).Where("id = ?", d.ID).Select("attempts", "status_code", "response_body", "error", "delivered_at").Updates(d).Error
+	}
+	if err != nil {
+		Logger().Error("Failed to save webhook delivery", Logger().Attrs(c, []any{slog.Any("err", err)})...)
+	}
+}
+
+// WebhookSubscription is the subscription of the webhook, the event is sent to the url when it matches the events filter.
+type WebhookSubscription struct {
+	Model
+	ID          NullUUID     `json:"id"          db:"m.id"          gorm:"column:id;primaryKey"`
+	URL         NullString   `json:"url"         db:"m.url"         gorm:"column:url"    validate:"required,url"`
+	Events      NullText     `json:"events"      db:"m.events"      gorm:"column:events" validate:"required"`
+	Secret      NullString   `json:"secret"      db:"m.secret"      gorm:"column:secret" validate:"required"`
+	Description NullText     `json:"description" db:"m.description" gorm:"column:description"`
+	IsActive    NullBool     `json:"is_active"   db:"m.is_active"   gorm:"column:is_active;default:true"`
+	CreatedAt   NullDateTime `json:"created_at"  db:"m.created_at"  gorm:"column:created_at"`
+	UpdatedAt   NullDateTime `json:"updated_at"  db:"m.updated_at"  gorm:"column:updated_at"`
+	DeletedAt   NullDateTime `json:"deleted_at"  db:"-"             gorm:"column:deleted_at"`
+}
+
+// EndPoint returns the WebhookSubscription end point, it used for cache key, etc.
+func (WebhookSubscription) EndPoint() string {
+	return "webhooks"
+}
+
+// TableVersion returns the versions of the WebhookSubscription table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (WebhookSubscription) TableVersion() string {
+	return "2026-10-19_11.00"
+}
+
+// TableName returns the name of the WebhookSubscription table in the database.
+func (WebhookSubscription) TableName() string {
+	return "webhooks"
+}
+
+// TableAliasName returns the table alias name of the WebhookSubscription table, used for querying.
+func (WebhookSubscription) TableAliasName() string {
+	return "m"
+}
+
+// GetRelations returns the relations of the WebhookSubscription data in the database, used for querying.
+func (m *WebhookSubscription) GetRelations() map[string]map[string]any {
+	return m.Relations
+}
+
+// GetFilters returns the filter of the WebhookSubscription data in the database, used for querying.
+func (m *WebhookSubscription) GetFilters() []map[string]any {
+	m.AddFilter(map[string]any{"column1": "m.deleted_at", "operator": "=", "value": nil})
+	return m.Filters
+}
+
+// GetSorts returns the default sort of the WebhookSubscription data in the database, used for querying.
+func (m *WebhookSubscription) GetSorts() []map[string]any {
+	m.AddSort(map[string]any{"column": "m.updated_at", "direction": "desc"})
+	return m.Sorts
+}
+
+// GetFields returns list of the field of the WebhookSubscription data in the database, used for querying.
+func (m *WebhookSubscription) GetFields() map[string]map[string]any {
+	m.SetFields(m)
+	return m.Fields
+}
+
+// GetSchema returns the WebhookSubscription schema, used for querying.
+func (m *WebhookSubscription) GetSchema() map[string]any {
+	return m.SetSchema(m)
+}
+
+// OpenAPISchemaName returns the name of the WebhookSubscription schema in the open api documentation.
+func (WebhookSubscription) OpenAPISchemaName() string {
+	return "WebhookSubscription"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the WebhookSubscription in the open api documentation.
+func (m *WebhookSubscription) GetOpenAPISchema() map[string]any {
+	return m.SetOpenAPISchema(m)
+}
+
+// IsMatch reports whether the event matches the comma separated events filter of the subscription.
+// The filter can be the exact event (`end_point.created`), all events of the entity (`end_point.*`) or all events (`*`).
+func (m WebhookSubscription) IsMatch(event string) bool {
+	entity, _, _ := strings.Cut(event, ".")
+	for _, e := range strings.Split(m.Events.String, ",") {
+		e = strings.TrimSpace(e)
+		if e == "*" || e == event || e == entity+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookSubscriptionList struct {
+	ListModel
+	Data []WebhookSubscription `json:"results"`
+}
+
+// OpenAPISchemaName returns the name of the WebhookSubscriptionList schema in the open api documentation.
+func (WebhookSubscriptionList) OpenAPISchemaName() string {
+	return "WebhookSubscriptionList"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the WebhookSubscriptionList in the open api documentation.
+func (p *WebhookSubscriptionList) GetOpenAPISchema() map[string]any {
+	return p.SetOpenAPISchema(&WebhookSubscription{})
+}
+
+// WebhookDelivery is the delivery log of the webhook.
+type WebhookDelivery struct {
+	Model
+	ID           NullUUID     `json:"id"            db:"m.id"            gorm:"column:id;primaryKey"`
+	WebhookID    NullUUID     `json:"webhook.id"    db:"m.webhook_id"    gorm:"column:webhook_id;index"`
+	Event        NullString   `json:"event"         db:"m.event"         gorm:"column:event"`
+	Payload      NullText     `json:"payload"       db:"m.payload"       gorm:"column:payload"`
+	Attempts     NullInt64    `json:"attempts"      db:"m.attempts"      gorm:"column:attempts"`
+	StatusCode   NullInt64    `json:"status_code"   db:"m.status_code"   gorm:"column:status_code"`
+	ResponseBody NullText     `json:"response_body" db:"m.response_body" gorm:"column:response_body"`
+	Error        NullText     `json:"error"         db:"m.error"         gorm:"column:error"`
+	RedeliveryOf NullUUID     `json:"redelivery_of" db:"m.redelivery_of" gorm:"column:redelivery_of"`
+	CreatedAt    NullDateTime `json:"created_at"    db:"m.created_at"    gorm:"column:created_at"`
+	DeliveredAt  NullDateTime `json:"delivered_at"  db:"m.delivered_at"  gorm:"column:delivered_at"`
+}
+
+// EndPoint returns the WebhookDelivery end point, it used for cache key, etc.
+func (WebhookDelivery) EndPoint() string {
+	return "webhook_deliveries"
+}
+
+// TableVersion returns the versions of the WebhookDelivery table in the database.
+// Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
+func (WebhookDelivery) TableVersion() string {
+	return "2026-10-19_11.00"
+}
+
+// TableName returns the name of the WebhookDelivery table in the database.
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// TableAliasName returns the table alias name of the WebhookDelivery table, used for querying.
+func (WebhookDelivery) TableAliasName() string {
+	return "m"
+}
+
+// GetRelations returns the relations of the WebhookDelivery data in the database, used for querying.
+func (m *WebhookDelivery) GetRelations() map[string]map[string]any {
+	return m.Relations
+}
+
+// GetFilters returns the filter of the WebhookDelivery data in the database, used for querying.
+func (m *WebhookDelivery) GetFilters() []map[string]any {
+	return m.Filters
+}
+
+// GetSorts returns the default sort of the WebhookDelivery data in the database, used for querying.
+func (m *WebhookDelivery) GetSorts() []map[string]any {
+	m.AddSort(map[string]any{"column": "m.created_at", "direction": "desc"})
+	return m.Sorts
+}
+
+// GetFields returns list of the field of the WebhookDelivery data in the database, used for querying.
+func (m *WebhookDelivery) GetFields() map[string]map[string]any {
+	m.SetFields(m)
+	return m.Fields
+}
+
+// GetSchema returns the WebhookDelivery schema, used for querying.
+func (m *WebhookDelivery) GetSchema() map[string]any {
+	return m.SetSchema(m)
+}
+
+// OpenAPISchemaName returns the name of the WebhookDelivery schema in the open api documentation.
+func (WebhookDelivery) OpenAPISchemaName() string {
+	return "WebhookDelivery"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the WebhookDelivery in the open api documentation.
+func (m *WebhookDelivery) GetOpenAPISchema() map[string]any {
+	return m.SetOpenAPISchema(m)
+}
+
+type WebhookDeliveryList struct {
+	ListModel
+	Data []WebhookDelivery `json:"results"`
+}
+
+// OpenAPISchemaName returns the name of the WebhookDeliveryList schema in the open api documentation.
+func (WebhookDeliveryList) OpenAPISchemaName() string {
+	return "WebhookDeliveryList"
+}
+
+// GetOpenAPISchema returns the Open API Schema of the WebhookDeliveryList in the open api documentation.
+func (p *WebhookDeliveryList) GetOpenAPISchema() map[string]any {
+	return p.SetOpenAPISchema(&WebhookDelivery{})
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestWebhookDeliver(t *testing.T) {
+	secret := "whsec_test"
+	payload := `{"event":"end_point.created","data":{"name":"Kilogram"}}`
+	attempts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		if r.Header.Get("X-Webhook-Signature") != "sha256="+Webhook().Sign(secret, timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer receiver.Close()
+
+	w := &webhookUtil{MaxAttempts: 5, Client: receiver.Client()}
+	s := WebhookSubscription{URL: NewNullString(receiver.URL), Secret: NewNullString(secret)}
+	d := WebhookDelivery{ID: NewNullUUID(), Event: NewNullString("end_point.created"), Payload: NewNullText(payload)}
+	for i := 1; i <= 2; i++ {
+		if err := w.Deliver(&d, s); err == nil {
+			t.Fatalf("Expected error on attempt %v with status %v", i, http.StatusServiceUnavailable)
+		}
+		if d.Attempts.Int64 != int64(i) || d.StatusCode.Int64 != http.StatusServiceUnavailable || !d.Error.Valid || d.DeliveredAt.Valid {
+			t.Errorf("Expected the failed attempt %v is set to the delivery, got [%+v]", i, d)
+		}
+	}
+	if err := w.Deliver(&d, s); err != nil {
+		t.Fatalf("Expected no error, got [%v]", err)
+	}
+
+	if d.Attempts.Int64 != 3 {
+		t.Errorf("Expected attempts [%v], got [%v]", 3, d.Attempts.Int64)
+	}
+	if d.StatusCode.Int64 != http.StatusOK {
+		t.Errorf("Expected status code [%v], got [%v]", http.StatusOK, d.StatusCode.Int64)
+	}
+	if d.ResponseBody.String != "ok" {
+		t.Errorf("Expected response body [%v], got [%v]", "ok", d.ResponseBody.String)
+	}
+	if !d.DeliveredAt.Valid {
+		t.Errorf("Expected delivered at is set")
+	}
+}
+
+func TestWebhookSubscriptionIsMatch(t *testing.T) {
+	s := WebhookSubscription{Events: NewNullText("contacts.created, products.*")}
+	for event, expected := range map[string]bool{
+		"contacts.created":  true,
+		"contacts.deleted":  false,
+		"products.updated":  true,
+		"categories.create": false,
+	} {
+		if s.IsMatch(event) != expected {
+			t.Errorf("Expected IsMatch(%v) [%v], got [%v]", event, expected, !expected)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"grest.dev/cmd/codegentemplate/app"
 	"grest.dev/cmd/codegentemplate/src"
@@ -14,12 +17,29 @@ var f embed.FS
 
 func main() {
 	app.Config()
+	if len(os.Args) == 2 && os.Args[1] == "config" {
+		app.Config().Dump(os.Stdout)
+		os.Exit(0)
+	}
 	if len(os.Args) == 2 && os.Args[1] == "update" {
 		app.IS_GENERATE_OPEN_API_DOC = true
 		src.Router()
 		app.OpenAPI().Configure().Generate()
 		os.Exit(0)
 	}
+	if len(os.Args) == 3 && os.Args[1] == "crypto" && os.Args[2] == "rotate" {
+		app.Logger()
+		app.DB()
+		src.Migrator()
+		count, err := app.Crypto().Rotate()
+		if err != nil {
+			app.Logger().Fatal("Failed to rotate the crypto key", slog.Int64("rows", count), slog.Any("err", err))
+		}
+		app.Logger().Info("The crypto key is rotated", slog.Int64("rows", count))
+		app.DB().Close()
+		app.Logger().Close()
+		os.Exit(0)
+	}
 
 	app.Logger()
 	app.Cache()
@@ -27,7 +47,6 @@ func main() {
 	app.Translator()
 	app.FS()
 	app.DB()
-	defer app.DB().Close()
 	app.Server()
 
 	src.Middleware()
@@ -39,8 +58,37 @@ func main() {
 	src.Migrator()
 	src.Seeder()
 	src.Scheduler()
-	err := app.Server().Start()
+	src.Worker()
+	app.Job().Start()
+	app.Cache().StartInvalidation()
+	go func() {
+		err := app.Server().Start()
+		if err != nil {
+			app.Logger().Fatal("Failed to start web server", slog.Any("err", err))
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	shutdown()
+}
+
+// shutdown gracefully shuts down the app within app.SHUTDOWN_TIMEOUT.
+// It stops the web server and waits for in-flight requests and background process,
+// stops the scheduler and the job workers, then closes the db, cache and log file.
+func shutdown() {
+	app.Logger().Info("Shutting down the server")
+	ctx, cancel := context.WithTimeout(context.Background(), app.SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	err := app.Server().Shutdown(ctx)
 	if err != nil {
-		app.Logger().Fatal("Failed to start web server", slog.Any("err", err))
+		app.Logger().Error("Failed to gracefully shut down the web server", slog.Any("err", err))
 	}
+	src.Scheduler().Stop(ctx)
+	app.Job().Stop(ctx)
+	app.DB().Close()
+	app.Cache().Close()
+	app.Logger().Close()
 }
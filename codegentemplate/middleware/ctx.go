@@ -21,16 +21,18 @@ type ctxHandler struct{}
 
 func (*ctxHandler) New(c *fiber.Ctx) error {
 	action := app.Action{
-		Method: c.Method(),
-		Path:   c.Path(),
+		Method:  c.Method(),
+		Path:    c.Path(),
+		IfMatch: c.Get("If-Match"),
 	}
 	lang := c.Get("Accept-Language")
 	if lang == "" || lang == "*" || strings.Contains(lang, ",") || strings.Contains(lang, ";") {
 		lang = "en"
 	}
 	ctx := app.Ctx{
-		Lang:   lang,
-		Action: action,
+		Lang:      lang,
+		Action:    action,
+		IsNoCache: strings.Contains(c.Get(fiber.HeaderCacheControl), "no-cache"),
 	}
 	c.Locals("ctx", &ctx)
 	return c.Next()
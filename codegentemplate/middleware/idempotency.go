@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+func Idempotency() *idempotencyHandler {
+	if ih == nil {
+		ih = &idempotencyHandler{}
+	}
+	return ih
+}
+
+var ih *idempotencyHandler
+
+type idempotencyHandler struct{}
+
+// idempotencyRecord is the state of the request with the Idempotency-Key header stored in the cache.
+type idempotencyRecord struct {
+	IsDone      bool              `json:"is_done"`
+	Fingerprint string            `json:"fingerprint"`
+	StatusCode  int               `json:"status_code"`
+	Headers     map[string]string `json:"headers"`
+	Body        []byte            `json:"body"`
+}
+
+// New handles the POST request with the Idempotency-Key header, so the client can safely retry the request.
+// The first response (except 5xx) is stored in the cache for app.IDEMPOTENCY_TTL and replayed for the retry with the same key,
+// scoped to the caller and the route. The retry while the first request is still processed is rejected with 409,
+// and the key reused for a different request body is rejected with 422.
+func (h *idempotencyHandler) New(c *fiber.Ctx) error {
+	key := c.Get("Idempotency-Key")
+	if c.Method() != http.MethodPost || key == "" {
+		return c.Next()
+	}
+	ctx, ok := c.Locals(app.CtxKey).(*app.Ctx)
+	if !ok {
+		return app.Error().New(http.StatusInternalServerError, "ctx is not found")
+	}
+	if len(key) > 255 {
+		return app.Error().New(http.StatusBadRequest, ctx.Trans("400_idempotency_key_invalid"))
+	}
+
+	cacheKey := "idempotency." + h.hash(h.caller(c, ctx), c.Method(), c.Path(), key)
+	record := idempotencyRecord{Fingerprint: h.hash(string(c.Body()))}
+	isLocked, err := app.Cache().SetNX(cacheKey, record, app.IDEMPOTENCY_LOCK_TIMEOUT)
+	if err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	if !isLocked {
+		return h.replay(c, ctx, cacheKey, record.Fingerprint)
+	}
+
+	err = c.Next()
+	if err != nil || c.Response().StatusCode() >= http.StatusInternalServerError {
+		// release the key, so the client can retry the failed request
+		app.Cache().Delete(cacheKey)
+		return err
+	}
+	record.IsDone = true
+	record.StatusCode = c.Response().StatusCode()
+	record.Headers = map[string]string{}
+	c.Response().Header.VisitAll(func(k, v []byte) {
+		if k := string(k); k != fiber.HeaderDate && k != fiber.HeaderContentLength && k != fiber.HeaderServer {
+			record.Headers[k] = string(v)
+		}
+	})
+	record.Body = c.Response().Body()
+	app.Cache().Set(cacheKey, record, app.IDEMPOTENCY_TTL)
+	return nil
+}
+
+// replay writes the stored response of the request with the same Idempotency-Key.
+func (*idempotencyHandler) replay(c *fiber.Ctx, ctx *app.Ctx, cacheKey, fingerprint string) error {
+	record := idempotencyRecord{}
+	if err := app.Cache().Get(cacheKey, &record); err != nil {
+		// the first request is just failed and the key is released
+		return app.Error().New(http.StatusConflict, ctx.Trans("409_idempotency_key_in_use"))
+	}
+	if record.Fingerprint != fingerprint {
+		return app.Error().New(http.StatusUnprocessableEntity, ctx.Trans("422_idempotency_key_mismatch"))
+	}
+	if !record.IsDone {
+		return app.Error().New(http.StatusConflict, ctx.Trans("409_idempotency_key_in_use"))
+	}
+	for k, v := range record.Headers {
+		c.Set(k, v)
+	}
+	c.Set("Idempotent-Replayed", "true")
+	return c.Status(record.StatusCode).Send(record.Body)
+}
+
+// caller returns the identity of the caller to scope the key, so the same key from the different caller is not replayed.
+func (*idempotencyHandler) caller(c *fiber.Ctx, ctx *app.Ctx) string {
+	if ctx.UserID != "" {
+		return "user:" + ctx.UserID
+	}
+	if auth := c.Get(fiber.HeaderAuthorization); auth != "" {
+		return "auth:" + auth
+	}
+	return "ip:" + c.IP()
+}
+
+func (*idempotencyHandler) hash(s ...string) string {
+	h := sha256.New()
+	for _, v := range s {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
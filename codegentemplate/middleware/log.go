@@ -27,14 +27,18 @@ func (l *logHandler) New(c *fiber.Ctx) error {
 	if c.Path() == "/api/version" {
 		return nil
 	}
+	app.Alerter().ObserveRequest(c.Response().StatusCode(), time.Since(startAt))
 	level := slog.LevelInfo
 	if err != nil || c.Response().StatusCode() >= http.StatusInternalServerError || c.Response().StatusCode() < http.StatusOK {
 		level = slog.LevelError
 	} else if c.Response().StatusCode() >= http.StatusBadRequest {
 		level = slog.LevelWarn
 	}
+	if level == slog.LevelInfo && c.Response().StatusCode() < http.StatusMultipleChoices && !app.Logger().IsSampled() {
+		return nil
+	}
 	ctx, attrs := l.getAttrs(c, startAt)
-	go l.send(ctx, level, attrs)
+	l.send(ctx, level, attrs) // the log is queued, it doesn't block the request
 	return nil
 }
 
@@ -60,7 +64,7 @@ func (*logHandler) getAttrs(c *fiber.Ctx, startAt time.Time) (app.Ctx, []any) {
 	if app.LOG_WITH_REQUEST_BODY {
 		attrs = append(attrs, slog.String("body_request", string(c.Body())))
 	}
-	if app.LOG_WITH_RESPONSE_BODY {
+	if app.LOG_WITH_RESPONSE_BODY && !c.Response().IsBodyStream() { // reading the streamed body (export) loads the whole file
 		attrs = append(attrs, slog.String("body_response", string(c.Response().Body())))
 	}
 	return *ctx, attrs
@@ -72,7 +76,9 @@ func (*logHandler) send(ctx app.Ctx, level slog.Level, attrs []any) error {
 		msg = app.Error().GetError(ctx.Err).OriginalMessage()
 	}
 	attrs = app.Logger().Attrs(ctx, attrs)
-	if level == slog.LevelError {
+	if level == slog.LevelError && !app.ALERT_REQUEST_ERRORS {
+		app.Logger().LogWithoutAlert(slog.LevelError, msg, attrs...) // alerted by ALERT_ERROR_RATE_THRESHOLD instead
+	} else if level == slog.LevelError {
 		app.Logger().Error(msg, attrs...)
 	} else if level == slog.LevelWarn {
 		app.Logger().Warn(msg, attrs...)
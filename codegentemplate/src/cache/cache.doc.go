@@ -0,0 +1,2 @@
+// cache is a package to inspect the cache statistics and keys by prefix, and to flush the cached keys.
+package cache
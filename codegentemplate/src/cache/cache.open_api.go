@@ -0,0 +1,64 @@
+package cache
+
+import "grest.dev/cmd/codegentemplate/app"
+
+// OpenAPI is constructor for *openAPI, to autogenerate open api document.
+func OpenAPI() *OpenAPIOperation {
+	return &OpenAPIOperation{}
+}
+
+// OpenAPIOperation embed from app.OpenAPIOperation for simplicity, used for autogenerate open api document.
+type OpenAPIOperation struct {
+	app.OpenAPIOperation
+}
+
+// Base is common detail of cache open api document component.
+func (o *OpenAPIOperation) Base() {
+	o.Tags = []string{"Cache"}
+	o.HeaderParams = []map[string]any{{"$ref": "#/components/parameters/headerParam.Accept-Language"}}
+	o.QueryParams = []map[string]any{
+		{"in": "query", "name": "prefix", "description": "The prefix of the cache keys, for example the end point", "schema": map[string]any{"type": "string"}},
+	}
+	o.Responses = map[string]map[string]any{
+		"200": {
+			"description": "Success",
+			"content":     map[string]any{"application/json": &app.CacheStats{}},
+		},
+		"400": app.OpenAPIError().BadRequest(),
+		"401": app.OpenAPIError().Unauthorized(),
+		"403": app.OpenAPIError().Forbidden(),
+	}
+	o.Securities = []map[string][]string{}
+}
+
+// Get is detail of `GET /api/cache` open api document component.
+func (o *OpenAPIOperation) Get() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get Cache Stats"
+	o.Description = "Use this method to get the hit, miss and eviction statistics of the cache with the cached keys by prefix"
+	o.QueryParams = append(o.QueryParams, map[string]any{
+		"in": "query", "name": "limit", "description": "The maximum number of keys, default = 100", "schema": map[string]any{"type": "integer"},
+	})
+	return o
+}
+
+// Flush is detail of `DELETE /api/cache` open api document component.
+func (o *OpenAPIOperation) Flush() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Flush Cache"
+	o.Description = "Use this method to delete the cached keys by prefix on every instance"
+	o.QueryParams[0]["required"] = true
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.CountResponse{}},
+	}
+	return o
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// UseCase returns a useCase for expected use case functional.
+func UseCase(ctx app.Ctx, query ...url.Values) useCase {
+	u := useCase{
+		Ctx:   &ctx,
+		Query: url.Values{},
+	}
+	if len(query) > 0 {
+		u.Query = query[0]
+	}
+	return u
+}
+
+// useCase provides a convenient interface for cache use case, use UseCase to access useCase.
+type useCase struct {
+
+	// injectable dependencies
+	Ctx   *app.Ctx   `json:"-" db:"-" gorm:"-"`
+	Query url.Values `json:"-" db:"-" gorm:"-"`
+}
+
+// Get returns the cache statistics with the cached keys of the prefix query param (for example the end point), up to 100 keys or the limit query param.
+func (u useCase) Get() (app.CacheStats, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("cache.stats")
+	if err != nil {
+		return app.CacheStats{}, err
+	}
+	limit, err := strconv.Atoi(u.Query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	res, err := app.Cache().Stats(u.Query.Get("prefix"), limit)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return res, nil
+}
+
+// Flush deletes the cached keys of the prefix query param on every instance, it returns the number of deleted keys on this instance.
+func (u useCase) Flush() (int64, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("cache.flush")
+	if err != nil {
+		return 0, err
+	}
+	prefix := u.Query.Get("prefix")
+	if prefix == "" {
+		return 0, app.Error().New(http.StatusBadRequest, u.Ctx.Trans("cache_prefix_required"))
+	}
+	count, err := app.Cache().Flush(prefix)
+	if err != nil {
+		return count, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return count, nil
+}
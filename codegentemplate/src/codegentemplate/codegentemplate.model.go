@@ -1,6 +1,11 @@
 package codegentemplate
 
-import "grest.dev/cmd/codegentemplate/app"
+import (
+	"net/url"
+	"time"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
 
 // CodeGenTemplate is the main model of CodeGenTemplate data. It provides a convenient interface for app.ModelInterface
 type CodeGenTemplate struct {
@@ -10,6 +15,7 @@ type CodeGenTemplate struct {
 	CreatedAt app.NullDateTime `json:"created_at" db:"m.created_at"      gorm:"column:created_at"`
 	UpdatedAt app.NullDateTime `json:"updated_at" db:"m.updated_at"      gorm:"column:updated_at"`
 	DeletedAt app.NullDateTime `json:"deleted_at" db:"-"                 gorm:"column:deleted_at"`
+	IsTrash   bool             `json:"-"          db:"-"                 gorm:"-"`
 }
 
 // EndPoint returns the CodeGenTemplate end point, it used for cache key, etc.
@@ -17,6 +23,23 @@ func (CodeGenTemplate) EndPoint() string {
 	return "end_point"
 }
 
+// TrashRetentionDays returns how many days the deleted CodeGenTemplate data is kept on the trash before it is purged.
+// Return 0 to keep the deleted data forever.
+func (CodeGenTemplate) TrashRetentionDays() int {
+	return app.TRASH_RETENTION_DAYS
+}
+
+// CacheTTL returns how long the CodeGenTemplate data is cached.
+// Return 0 to disable the cache.
+func (CodeGenTemplate) CacheTTL() time.Duration {
+	return app.CACHE_TTL
+}
+
+// Async returns the async use case of CodeGenTemplate, it used by app.Ctx.Hook to get the latest data.
+func (CodeGenTemplate) Async(ctx app.Ctx, query ...url.Values) useCase {
+	return useCase{}.Async(ctx, query...)
+}
+
 // TableVersion returns the versions of the CodeGenTemplate table in the database.
 // Change this value with date format YYYY-MM-DD_HH.ii when any table structure changes.
 func (CodeGenTemplate) TableVersion() string {
@@ -41,8 +64,13 @@ func (m *CodeGenTemplate) GetRelations() map[string]map[string]any {
 }
 
 // GetFilters returns the filter of the CodeGenTemplate data in the database, used for querying.
+// The deleted data is only returned if IsTrash is true.
 func (m *CodeGenTemplate) GetFilters() []map[string]any {
-	m.AddFilter(map[string]any{"column1": "m.deleted_at", "operator": "=", "value": nil})
+	if m.IsTrash {
+		m.AddFilter(map[string]any{"column1": "m.deleted_at", "operator": "!=", "value": nil})
+	} else {
+		m.AddFilter(map[string]any{"column1": "m.deleted_at", "operator": "=", "value": nil})
+	}
 	return m.Filters
 }
 
@@ -103,6 +131,7 @@ type ParamUpdate struct {
 type ParamPartiallyUpdate struct {
 	CodeGenTemplate
 	Reason app.NullString `json:"reason" gorm:"-" validate:"required"`
+	Fields []string       `json:"-"      gorm:"-"` // the json keys of the request body, only these fields are updated (including the explicit null)
 }
 
 // ParamDelete is the expected parameters for delete the CodeGenTemplate data.
@@ -110,3 +139,9 @@ type ParamDelete struct {
 	CodeGenTemplate
 	Reason app.NullString `json:"reason" gorm:"-" validate:"required"`
 }
+
+// ParamRestore is the expected parameters for restore the deleted CodeGenTemplate data.
+type ParamRestore struct {
+	CodeGenTemplate
+	Reason app.NullString `json:"reason" gorm:"-" validate:"required"`
+}
@@ -36,12 +36,24 @@ func (o *OpenAPIOperation) Get() *OpenAPIOperation {
 
 	o.Base()
 	o.Summary = "Get CodeGenTemplate"
-	o.Description = "Use this method to get list of CodeGenTemplate"
-	o.QueryParams = []map[string]any{{"$ref": "#/components/parameters/queryParam.Any"}}
+	o.Description = "Use this method to get list of CodeGenTemplate. " +
+		"Set the Accept header to text/csv, application/x-ndjson or the xlsx content type (or set $format to csv, ndjson or xlsx) to download all the filtered data as a file. " +
+		"Set $envelope to false to get only the results array, the pagination info is available on the Link and X-Total-Count headers."
+	o.QueryParams = []map[string]any{
+		{"$ref": "#/components/parameters/queryParam.Any"},
+		{"$ref": "#/components/parameters/queryParam.Format"},
+		{"$ref": "#/components/parameters/queryParam.Envelope"},
+	}
 	o.Responses = map[string]map[string]any{
 		"200": {
 			"description": "Success",
-			"content":     map[string]any{"application/json": &CodeGenTemplateList{}}, // will auto create schema $ref: '#/components/schemas/CodeGenTemplate.List' if not exists
+			"headers":     app.OpenAPIListHeaders(),
+			"content": map[string]any{
+				"application/json": &CodeGenTemplateList{}, // will auto create schema $ref: '#/components/schemas/CodeGenTemplate.List' if not exists
+				app.MIMECSV:        &app.ExportFile{},
+				app.MIMEXLSX:       &app.ExportFile{},
+				app.MIMENDJSON:     &app.ExportFile{},
+			},
 		},
 		"400": app.OpenAPIError().BadRequest(),
 		"401": app.OpenAPIError().Unauthorized(),
@@ -60,6 +72,44 @@ func (o *OpenAPIOperation) GetByID() *OpenAPIOperation {
 	o.Summary = "Get CodeGenTemplate By ID"
 	o.Description = "Use this method to get CodeGenTemplate by id"
 	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
+	o.HeaderParams = append(o.HeaderParams, map[string]any{"$ref": "#/components/parameters/headerParam.If-None-Match"})
+	o.Responses["200"]["headers"] = map[string]any{"ETag": app.OpenAPIETagHeader()}
+	o.Responses["304"] = map[string]any{"description": "Not Modified"}
+	return o
+}
+
+// GetHistory is detail of `GET /api/v3/end_point/{id}/history` open api document component.
+func (o *OpenAPIOperation) GetHistory() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get CodeGenTemplate History By ID"
+	o.Description = "Use this method to get list of activity log (who changed what and why) of CodeGenTemplate by id"
+	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
+	o.QueryParams = []map[string]any{{"$ref": "#/components/parameters/queryParam.Any"}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.ActivityLogList{}}, // will auto create schema $ref: '#/components/schemas/ActivityLogList' if not exists
+	}
+	return o
+}
+
+// GetTrash is detail of `GET /api/v3/end_point/trash` open api document component.
+func (o *OpenAPIOperation) GetTrash() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get CodeGenTemplate Trash"
+	o.Description = "Use this method to get list of deleted CodeGenTemplate which is not purged yet"
+	o.QueryParams = []map[string]any{{"$ref": "#/components/parameters/queryParam.Any"}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &CodeGenTemplateList{}},
+	}
 	return o
 }
 
@@ -73,6 +123,9 @@ func (o *OpenAPIOperation) Create() *OpenAPIOperation {
 	o.Summary = "Create CodeGenTemplate"
 	o.Description = "Use this method to create CodeGenTemplate"
 	o.Body = map[string]any{"application/json": &ParamCreate{}}
+	o.HeaderParams = append(o.HeaderParams, map[string]any{"$ref": "#/components/parameters/headerParam.Idempotency-Key"})
+	o.Responses["409"] = map[string]any{"description": "The request with the same Idempotency-Key is being processed"}
+	o.Responses["422"] = map[string]any{"description": "The Idempotency-Key has been used for a different request"}
 	return o
 }
 
@@ -87,6 +140,10 @@ func (o *OpenAPIOperation) UpdateByID() *OpenAPIOperation {
 	o.Description = "Use this method to update CodeGenTemplate by id"
 	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
 	o.Body = map[string]any{"application/json": &ParamUpdate{}}
+	o.HeaderParams = append(o.HeaderParams, map[string]any{"$ref": "#/components/parameters/headerParam.If-Match"})
+	o.Responses["200"]["headers"] = map[string]any{"ETag": app.OpenAPIETagHeader()}
+	o.Responses["412"] = map[string]any{"description": "The data has been changed by another user"}
+	o.Responses["428"] = map[string]any{"description": "The If-Match header is required"}
 	return o
 }
 
@@ -98,9 +155,20 @@ func (o *OpenAPIOperation) PartiallyUpdateByID() *OpenAPIOperation {
 
 	o.Base()
 	o.Summary = "Partially Update CodeGenTemplate By ID"
-	o.Description = "Use this method to partially update CodeGenTemplate by id"
+	o.Description = "Use this method to partially update CodeGenTemplate by id, only the fields on the request body are updated (JSON Merge Patch), " +
+		"the field can be cleared by sending null. The JSON Patch operations are also supported with the application/json-patch+json content type, " +
+		"the reason is sent with {\"op\":\"add\",\"path\":\"/reason\",\"value\":\"...\"}"
 	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
-	o.Body = map[string]any{"application/json": &ParamPartiallyUpdate{}}
+	o.Body = map[string]any{
+		"application/json": &ParamPartiallyUpdate{},
+		app.MIMEMergePatch: &ParamPartiallyUpdate{},
+		app.MIMEJSONPatch:  &app.JSONPatch{},
+	}
+	o.HeaderParams = append(o.HeaderParams, map[string]any{"$ref": "#/components/parameters/headerParam.If-Match"})
+	o.Responses["200"]["headers"] = map[string]any{"ETag": app.OpenAPIETagHeader()}
+	o.Responses["412"] = map[string]any{"description": "The data has been changed by another user"}
+	o.Responses["422"] = map[string]any{"description": "The JSON Patch operation is failed"}
+	o.Responses["428"] = map[string]any{"description": "The If-Match header is required"}
 	return o
 }
 
@@ -115,5 +183,149 @@ func (o *OpenAPIOperation) DeleteByID() *OpenAPIOperation {
 	o.Description = "Use this method to delete CodeGenTemplate by id"
 	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
 	o.Body = map[string]any{"application/json": &ParamDelete{}}
+	o.HeaderParams = append(o.HeaderParams, map[string]any{"$ref": "#/components/parameters/headerParam.If-Match"})
+	o.Responses["412"] = map[string]any{"description": "The data has been changed by another user"}
+	o.Responses["428"] = map[string]any{"description": "The If-Match header is required"}
+	return o
+}
+
+// RestoreByID is detail of `POST /api/v3/end_point/{id}/restore` open api document component.
+func (o *OpenAPIOperation) RestoreByID() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Restore CodeGenTemplate By ID"
+	o.Description = "Use this method to restore deleted CodeGenTemplate by id from the trash"
+	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
+	o.Body = map[string]any{"application/json": &ParamRestore{}}
+	return o
+}
+
+// PurgeTrash is detail of `DELETE /api/v3/end_point/trash` open api document component.
+func (o *OpenAPIOperation) PurgeTrash() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Purge CodeGenTemplate Trash"
+	o.Description = "Use this method to permanently delete CodeGenTemplate which is deleted longer than the retention period. " +
+		"It is also run daily by the scheduler."
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.CountResponse{}},
+	}
+	return o
+}
+
+// BulkCreate is detail of `POST /api/v3/end_point/bulk` open api document component.
+func (o *OpenAPIOperation) BulkCreate() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Bulk Create CodeGenTemplate"
+	o.Description = "Use this method to create many CodeGenTemplate in one request, each item is the parameters of create CodeGenTemplate. " +
+		"With `all_or_nothing` mode (default) nothing is saved if any item is failed, with `best_effort` mode the succeeded items are saved."
+	o.Body = map[string]any{"application/json": &app.BulkParam{}}
+	o.HeaderParams = append(o.HeaderParams, map[string]any{"$ref": "#/components/parameters/headerParam.Idempotency-Key"})
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	o.Responses["207"] = map[string]any{
+		"description": "Some items are failed on best_effort mode",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	o.Responses["422"] = map[string]any{
+		"description": "Some items are failed on all_or_nothing mode, nothing is saved",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	return o
+}
+
+// Import is detail of `POST /api/v3/end_point/import` open api document component.
+func (o *OpenAPIOperation) Import() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Import CodeGenTemplate"
+	o.Description = "Use this method to create many CodeGenTemplate from csv or xlsx file, the first row is the header with the field name. " +
+		"Set is_dry_run to true to validate the file without saving the data. " +
+		"The large file is imported on the background, use `GET /api/imports/{id}` to get the progress and the error report."
+	o.Body = map[string]any{"multipart/form-data": &app.ImportFile{}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.ImportRecord{}},
+	}
+	o.Responses["202"] = map[string]any{
+		"description": "The file is imported on the background",
+		"content":     map[string]any{"application/json": &app.ImportRecord{}},
+	}
+	o.Responses["207"] = map[string]any{
+		"description": "Some rows are failed on best_effort mode",
+		"content":     map[string]any{"application/json": &app.ImportRecord{}},
+	}
+	o.Responses["422"] = map[string]any{
+		"description": "Some rows are failed on all_or_nothing mode, nothing is saved",
+		"content":     map[string]any{"application/json": &app.ImportRecord{}},
+	}
+	return o
+}
+
+// BulkPartiallyUpdate is detail of `PATCH /api/v3/end_point/bulk` open api document component.
+func (o *OpenAPIOperation) BulkPartiallyUpdate() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Bulk Partially Update CodeGenTemplate"
+	o.Description = "Use this method to partially update many CodeGenTemplate in one request, each item is the parameters of partially update CodeGenTemplate with the id. " +
+		"With `all_or_nothing` mode (default) nothing is saved if any item is failed, with `best_effort` mode the succeeded items are saved."
+	o.Body = map[string]any{"application/json": &app.BulkParam{}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	o.Responses["207"] = map[string]any{
+		"description": "Some items are failed on best_effort mode",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	o.Responses["422"] = map[string]any{
+		"description": "Some items are failed on all_or_nothing mode, nothing is saved",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	return o
+}
+
+// BulkDelete is detail of `DELETE /api/v3/end_point/bulk` open api document component.
+func (o *OpenAPIOperation) BulkDelete() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Bulk Delete CodeGenTemplate"
+	o.Description = "Use this method to delete many CodeGenTemplate in one request, each item is the id and the reason to delete CodeGenTemplate. " +
+		"With `all_or_nothing` mode (default) nothing is saved if any item is failed, with `best_effort` mode the succeeded items are saved."
+	o.Body = map[string]any{"application/json": &app.BulkParam{}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	o.Responses["207"] = map[string]any{
+		"description": "Some items are failed on best_effort mode",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
+	o.Responses["422"] = map[string]any{
+		"description": "Some items are failed on all_or_nothing mode, nothing is saved",
+		"content":     map[string]any{"application/json": &app.BulkResult{}},
+	}
 	return o
 }
@@ -1,7 +1,11 @@
 package codegentemplate
 
 import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -37,6 +41,11 @@ func (r *restAPI) GetByID(c *fiber.Ctx) error {
 	if err != nil {
 		return app.Server().Error(c, err)
 	}
+	etag := app.ETag(res.ID.String, res.UpdatedAt)
+	c.Set(fiber.HeaderETag, etag)
+	if app.IsETagMatch(c.Get(fiber.HeaderIfNoneMatch), etag, true) {
+		return c.SendStatus(http.StatusNotModified)
+	}
 	return c.JSON(app.Query().Return(res, res.IsFlat()))
 }
 
@@ -45,6 +54,9 @@ func (r *restAPI) Get(c *fiber.Ctx) error {
 	if err := r.injectDeps(c); err != nil {
 		return app.Server().Error(c, err)
 	}
+	if format := app.Query().ExportFormat(c.Get(fiber.HeaderAccept), c.Query(app.QueryFormat)); format != "" {
+		return r.export(c, format)
+	}
 	res, err := r.UseCase.Get()
 	if err != nil {
 		return app.Server().Error(c, err)
@@ -54,6 +66,52 @@ func (r *restAPI) Get(c *fiber.Ctx) error {
 	return c.JSON(app.Query().Return(res, model.IsFlat()))
 }
 
+// export streams the list of codegentemplate data as csv, xlsx or ndjson file.
+func (r *restAPI) export(c *fiber.Ctx, format string) error {
+	write, err := r.UseCase.Export(format)
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	ctx := *r.UseCase.Ctx
+	c.Set(fiber.HeaderContentType, app.Query().ExportContentType(format))
+	c.Attachment(CodeGenTemplate{}.EndPoint() + "." + format)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(w); err != nil {
+			app.Logger().Error("Failed to export data", app.Logger().Attrs(ctx, []any{slog.Any("err", err)})...)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// GetHistory is the REST API handler for `GET /api/v3/end_point/{id}/history`.
+func (r *restAPI) GetHistory(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetHistory(c.Params("id"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	res.SetLink(c)
+	model := &app.ActivityLog{}
+	return c.JSON(app.Query().Return(res, model.IsFlat()))
+}
+
+// GetTrash is the REST API handler for `GET /api/v3/end_point/trash`.
+func (r *restAPI) GetTrash(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetTrash()
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	res.SetLink(c)
+	model := &CodeGenTemplate{}
+	return c.JSON(app.Query().Return(res, model.IsFlat()))
+}
+
 // Create is the REST API handler for `POST /api/v3/end_point`.
 func (r *restAPI) Create(c *fiber.Ctx) error {
 	if err := r.injectDeps(c); err != nil {
@@ -74,6 +132,7 @@ func (r *restAPI) Create(c *fiber.Ctx) error {
 	if err != nil {
 		return app.Server().Error(c, err)
 	}
+	c.Set(fiber.HeaderETag, app.ETag(res.ID.String, res.UpdatedAt))
 	return c.Status(http.StatusCreated).JSON(app.Query().Return(res, res.IsFlat()))
 }
 
@@ -97,6 +156,7 @@ func (r *restAPI) UpdateByID(c *fiber.Ctx) error {
 	if err != nil {
 		return app.Server().Error(c, err)
 	}
+	c.Set(fiber.HeaderETag, app.ETag(res.ID.String, res.UpdatedAt))
 	return c.JSON(app.Query().Return(res, res.IsFlat()))
 }
 
@@ -105,11 +165,24 @@ func (r *restAPI) PartiallyUpdateByID(c *fiber.Ctx) error {
 	if err := r.injectDeps(c); err != nil {
 		return app.Server().Error(c, err)
 	}
+	body := c.Body()
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), app.MIMEJSONPatch) {
+		patch, err := r.jsonPatchToMergePatch(c.Params("id"), body)
+		if err != nil {
+			return app.Server().Error(c, err)
+		}
+		body = patch
+	}
 	param := &CodeGenTemplate{}
 	paramUpdate := &ParamPartiallyUpdate{}
-	if err := app.Query().BindJSON(c.Body(), param, paramUpdate); err != nil {
+	if err := app.Query().BindJSON(body, param, paramUpdate); err != nil {
 		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
 	}
+	fields, err := app.Query().JSONKeys(body)
+	if err != nil {
+		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
+	}
+	paramUpdate.Fields = fields
 	if err := r.UseCase.PartiallyUpdateByID(c.Params("id"), param, paramUpdate); err != nil {
 		return app.Server().Error(c, err)
 	}
@@ -120,9 +193,37 @@ func (r *restAPI) PartiallyUpdateByID(c *fiber.Ctx) error {
 	if err != nil {
 		return app.Server().Error(c, err)
 	}
+	c.Set(fiber.HeaderETag, app.ETag(res.ID.String, res.UpdatedAt))
 	return c.JSON(app.Query().Return(res, res.IsFlat()))
 }
 
+// jsonPatchToMergePatch applies the JSON Patch (RFC 6902) operations to the current data,
+// and returns the JSON Merge Patch (RFC 7396) of the changes. The reason can be sent with {"op":"add","path":"/reason","value":"..."}.
+// The current data is read from the db instead of the cache, and its etag is the If-Match of the update if the request has none,
+// so the patch is rejected with 412 if the data is changed after it is read.
+func (r *restAPI) jsonPatchToMergePatch(id string, patch []byte) ([]byte, error) {
+	old, err := r.UseCase.getPrevious(id)
+	if err != nil {
+		return nil, err
+	}
+	if r.UseCase.Ctx.Action.IfMatch == "" && !app.IS_REQUIRE_IF_MATCH {
+		r.UseCase.Ctx.Action.IfMatch = app.ETag(old.ID.String, old.UpdatedAt)
+	}
+	doc, err := json.Marshal(app.Query().Return(old, old.IsFlat()))
+	if err != nil {
+		return nil, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	newDoc, err := app.ApplyJSONPatch(doc, patch)
+	if err != nil {
+		return nil, app.Error().New(http.StatusUnprocessableEntity, err.Error())
+	}
+	mergePatch, err := app.MergePatchDiff(doc, newDoc)
+	if err != nil {
+		return nil, app.Error().New(http.StatusUnprocessableEntity, err.Error())
+	}
+	return mergePatch, nil
+}
+
 // DeleteByID is the REST API handler for `DELETE /api/v3/end_point/{id}`.
 func (r *restAPI) DeleteByID(c *fiber.Ctx) error {
 	err := r.injectDeps(c)
@@ -139,3 +240,103 @@ func (r *restAPI) DeleteByID(c *fiber.Ctx) error {
 	}
 	return c.JSON(r.UseCase.Ctx.Deleted(CodeGenTemplate{}.EndPoint(), "id", id))
 }
+
+// RestoreByID is the REST API handler for `POST /api/v3/end_point/{id}/restore`.
+func (r *restAPI) RestoreByID(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	paramRestore := &ParamRestore{}
+	if err := app.Query().BindJSON(c.Body(), paramRestore); err != nil {
+		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
+	}
+	if err := r.UseCase.RestoreByID(c.Params("id"), paramRestore); err != nil {
+		return app.Server().Error(c, err)
+	}
+	if r.UseCase.Query.Get("is_skip_return") == "true" {
+		return c.JSON(map[string]any{"message": "Success"})
+	}
+	res, err := r.UseCase.GetByID(c.Params("id"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	c.Set(fiber.HeaderETag, app.ETag(res.ID.String, res.UpdatedAt))
+	return c.JSON(app.Query().Return(res, res.IsFlat()))
+}
+
+// PurgeTrash is the REST API handler for `DELETE /api/v3/end_point/trash`.
+func (r *restAPI) PurgeTrash(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	count, err := r.UseCase.PurgeTrash()
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.JSON(app.CountResponse{Message: "Success", Count: count})
+}
+
+// BulkCreate is the REST API handler for `POST /api/v3/end_point/bulk`.
+func (r *restAPI) BulkCreate(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	param := app.BulkParam{}
+	if err := json.Unmarshal(c.Body(), &param); err != nil {
+		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
+	}
+	res, err := r.UseCase.BulkCreate(param)
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.Status(res.StatusCode(http.StatusCreated)).JSON(res)
+}
+
+// Import is the REST API handler for `POST /api/v3/end_point/import`.
+func (r *restAPI) Import(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	file, _ := c.FormFile("file")
+	param := app.ImportParam{Mode: c.FormValue("mode"), IsDryRun: c.FormValue("is_dry_run") == "true"}
+	res, err := r.UseCase.Import(file, param)
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	if res.StatusCode() == http.StatusAccepted {
+		c.Location("/api/" + app.ImportRecord{}.EndPoint() + "/" + res.ID.String)
+	}
+	return c.Status(res.StatusCode()).JSON(app.Query().Return(res, res.IsFlat()))
+}
+
+// BulkPartiallyUpdate is the REST API handler for `PATCH /api/v3/end_point/bulk`.
+func (r *restAPI) BulkPartiallyUpdate(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	param := app.BulkParam{}
+	if err := json.Unmarshal(c.Body(), &param); err != nil {
+		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
+	}
+	res, err := r.UseCase.BulkPartiallyUpdate(param)
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.Status(res.StatusCode(http.StatusOK)).JSON(res)
+}
+
+// BulkDelete is the REST API handler for `DELETE /api/v3/end_point/bulk`.
+func (r *restAPI) BulkDelete(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	param := app.BulkParam{}
+	if err := json.Unmarshal(c.Body(), &param); err != nil {
+		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
+	}
+	res, err := r.UseCase.BulkDelete(param)
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.Status(res.StatusCode(http.StatusOK)).JSON(res)
+}
@@ -18,6 +18,11 @@ func prepareTest(tb testing.TB) {
 	app.Test()
 	tx := app.Test().Tx
 	app.DB().RegisterTable("main", CodeGenTemplate{})
+	app.DB().RegisterTable("main", app.ActivityLog{})
+	app.DB().RegisterTable("main", app.JobRecord{})
+	app.DB().RegisterTable("main", app.ImportRecord{})
+	app.RegisterJob(CodeGenTemplate{}.EndPoint()+".hook", HookJob)
+	app.RegisterJob(CodeGenTemplate{}.EndPoint()+".import", ImportJob)
 	app.DB().MigrateTable(tx, "main", app.Setting{})
 	tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&CodeGenTemplate{})
 
@@ -27,13 +32,26 @@ func prepareTest(tb testing.TB) {
 		"end_point.create",
 		"end_point.edit",
 		"end_point.delete",
+		"end_point.history",
+		"end_point.trash",
+		"end_point.restore",
+		"end_point.purge",
+		"end_point.import",
 	}))
 	app.Server().AddRoute("/end_point", "POST", REST().Create, nil)
 	app.Server().AddRoute("/end_point", "GET", REST().Get, nil)
+	app.Server().AddRoute("/end_point/trash", "GET", REST().GetTrash, nil)
+	app.Server().AddRoute("/end_point/trash", "DELETE", REST().PurgeTrash, nil)
+	app.Server().AddRoute("/end_point/bulk", "POST", REST().BulkCreate, nil)
+	app.Server().AddRoute("/end_point/bulk", "PATCH", REST().BulkPartiallyUpdate, nil)
+	app.Server().AddRoute("/end_point/bulk", "DELETE", REST().BulkDelete, nil)
+	app.Server().AddRoute("/end_point/import", "POST", REST().Import, nil)
 	app.Server().AddRoute("/end_point/:id", "GET", REST().GetByID, nil)
 	app.Server().AddRoute("/end_point/:id", "PUT", REST().UpdateByID, nil)
 	app.Server().AddRoute("/end_point/:id", "PATCH", REST().PartiallyUpdateByID, nil)
 	app.Server().AddRoute("/end_point/:id", "DELETE", REST().DeleteByID, nil)
+	app.Server().AddRoute("/end_point/:id/history", "GET", REST().GetHistory, nil)
+	app.Server().AddRoute("/end_point/:id/restore", "POST", REST().RestoreByID, nil)
 }
 
 // getTestCodeGenTemplateID returns an available CodeGenTemplate ID.
@@ -50,6 +68,7 @@ var tests = []struct {
 	bodyRequest  string // body to test
 	expectedCode int    // expected HTTP status code
 	expectedBody string // expected body response
+	isRunJobs    bool   // run the due background jobs before the request
 }{
 	{
 		description:  "Get empty list of CodeGenTemplate",
@@ -94,6 +113,15 @@ var tests = []struct {
 		expectedCode: http.StatusOK,
 		expectedBody: `{"name":"Kilo Gram"}`,
 	},
+	{
+		description:  "Get CodeGenTemplate history by ID",
+		method:       "GET",
+		path:         "/end_point/" + getTestCodeGenTemplateID() + "/history",
+		token:        app.TestFullAccessToken,
+		expectedCode: http.StatusOK,
+		expectedBody: `{"results":[{"entity":"end_point"}]}`,
+		isRunJobs:    true,
+	},
 	{
 		description:  "Delete CodeGenTemplate by ID",
 		method:       "DELETE",
@@ -103,6 +131,49 @@ var tests = []struct {
 		expectedCode: http.StatusOK,
 		expectedBody: `{"code":200}`,
 	},
+	{
+		description:  "Get CodeGenTemplate trash",
+		method:       "GET",
+		path:         "/end_point/trash",
+		token:        app.TestFullAccessToken,
+		expectedCode: http.StatusOK,
+		expectedBody: `{"count":1,"results":[{"name":"Kilo Gram"}]}`,
+	},
+	{
+		description:  "Restore CodeGenTemplate by ID",
+		method:       "POST",
+		path:         "/end_point/" + getTestCodeGenTemplateID() + "/restore",
+		token:        app.TestFullAccessToken,
+		bodyRequest:  `{"reason":"Restore CodeGenTemplate by ID"}`,
+		expectedCode: http.StatusOK,
+		expectedBody: `{"name":"Kilo Gram"}`,
+	},
+	{
+		description:  "Purge CodeGenTemplate trash",
+		method:       "DELETE",
+		path:         "/end_point/trash",
+		token:        app.TestFullAccessToken,
+		expectedCode: http.StatusOK,
+		expectedBody: `{"count":0}`,
+	},
+	{
+		description:  "Bulk create CodeGenTemplate with all or nothing mode",
+		method:       "POST",
+		path:         "/end_point/bulk",
+		token:        app.TestFullAccessToken,
+		bodyRequest:  `{"items":[{"name":"Gram"},{"name":"Liter"}]}`,
+		expectedCode: http.StatusCreated,
+		expectedBody: `{"mode":"all_or_nothing","success_count":2,"failed_count":0}`,
+	},
+	{
+		description:  "Bulk delete CodeGenTemplate with best effort mode",
+		method:       "DELETE",
+		path:         "/end_point/bulk",
+		token:        app.TestFullAccessToken,
+		bodyRequest:  `{"mode":"best_effort","items":[{"id":"` + getTestCodeGenTemplateID() + `","reason":"Bulk delete"},{"reason":"Bulk delete"}]}`,
+		expectedCode: http.StatusMultiStatus,
+		expectedBody: `{"mode":"best_effort","success_count":1,"failed_count":1,"results":[{"index":0,"code":200},{"index":1,"code":400}]}`,
+	},
 }
 
 // TestCodeGenTemplateREST tests the REST API of CodeGenTemplate data with specified scenario.
@@ -112,6 +183,11 @@ func TestCodeGenTemplateREST(t *testing.T) {
 	// Iterate through test single test cases
 	for _, test := range tests {
 
+		// The history is saved by the hook job, run it before asserting the history
+		if test.isRunJobs {
+			app.Test().RunJobs()
+		}
+
 		// Create a new http request with the route from the test case
 		req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.bodyRequest))
 		req.Header.Add("Authorization", "Bearer "+test.token)
@@ -138,6 +214,9 @@ func BenchmarkCodeGenTemplateREST(b *testing.B) {
 	prepareTest(b)
 	for i := 0; i < b.N; i++ {
 		for _, test := range tests {
+			if test.isRunJobs {
+				app.Test().RunJobs()
+			}
 			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.bodyRequest))
 			req.Header.Add("Authorization", "Bearer "+test.token)
 			req.Header.Add("Content-Type", "application/json")
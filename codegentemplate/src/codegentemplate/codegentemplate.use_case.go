@@ -2,6 +2,8 @@ package codegentemplate
 
 import (
 	"encoding/json"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"time"
@@ -35,6 +37,23 @@ func (u useCase) Async(ctx app.Ctx, query ...url.Values) useCase {
 	return UseCase(ctx, query...)
 }
 
+// Enqueue enqueues the background job using the db transaction of current ctx,
+// so the job is only run if the data of this use case is committed.
+func (u useCase) Enqueue(name string, payload any, opts ...app.JobOption) error {
+	return app.Job().Enqueue(*u.Ctx, name, payload, opts...)
+}
+
+// HookJob is the handler of the "end_point.hook" job, it is registered on src/worker.go.
+func HookJob(ctx app.Ctx, p app.HookPayload) error {
+	return ctx.Hook(p)
+}
+
+// PurgeTrashTask is the scheduled task to purge the expired codegentemplate trash, it is registered on src/scheduler.go.
+func PurgeTrashTask(ctx app.Ctx) error {
+	_, err := UseCase(ctx).PurgeTrash()
+	return err
+}
+
 // GetByID returns the codegentemplate data for the specified ID.
 func (u useCase) GetByID(id string) (CodeGenTemplate, error) {
 	res := CodeGenTemplate{}
@@ -52,10 +71,105 @@ func (u useCase) GetByID(id string) (CodeGenTemplate, error) {
 	}
 	realID, err := u.GetIDByKey(key, id)
 
-	// get from cache and return if exists
+	// get from cache, or get from db and save to cache
 	cacheKey := CodeGenTemplate{}.EndPoint() + "." + id
-	app.Cache().Get(cacheKey, &res)
-	if res.ID.Valid {
+	u.Query.Add("id", realID.String)
+	err = app.Cache().Fetch(*u.Ctx, cacheKey, CodeGenTemplate{}, &res, func(ctx app.Ctx) (any, error) {
+		res := CodeGenTemplate{}
+
+		// prepare db for the ctx of the load, it is the async ctx on the background reload
+		tx, err := ctx.DB()
+		if err != nil {
+			return res, app.Error().New(http.StatusInternalServerError, err.Error())
+		}
+
+		// get from db
+		err = app.Query().First(tx, &res, u.Query)
+		if err != nil {
+			return res, ctx.NotFoundError(err, CodeGenTemplate{}.EndPoint(), key, id)
+		}
+		return res, nil
+	})
+	return res, err
+}
+
+// Get returns the list of codegentemplate data.
+func (u useCase) Get() (app.ListModel, error) {
+	res := app.ListModel{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.list")
+	if err != nil {
+		return res, err
+	}
+	// get from cache, or find data and save to cache
+	cacheKey := CodeGenTemplate{}.EndPoint() + "?" + u.Query.Encode()
+	err = app.Cache().Fetch(*u.Ctx, cacheKey, CodeGenTemplate{}, &res, func(ctx app.Ctx) (any, error) {
+		res := app.ListModel{}
+
+		// prepare db for the ctx of the load, it is the async ctx on the background reload
+		tx, err := ctx.DB()
+		if err != nil {
+			return res, app.Error().New(http.StatusInternalServerError, err.Error())
+		}
+
+		// find data with the cursor pagination if $cursor or $limit set
+		if app.Query().IsCursorPagination(u.Query) {
+			return app.Query().FindByCursor(tx, func() app.ModelInterface { return &CodeGenTemplate{} }, u.Query)
+		}
+
+		// set pagination info
+		res.Count,
+			res.PageContext.Page,
+			res.PageContext.PerPage,
+			res.PageContext.PageCount,
+			err = app.Query().PaginationInfo(tx, &CodeGenTemplate{}, u.Query)
+		if err != nil {
+			return res, app.Error().New(http.StatusInternalServerError, err.Error())
+		}
+		// return data count if $per_page set to 0
+		if res.PageContext.PerPage == 0 {
+			return res, nil
+		}
+
+		// find data
+		data, err := app.Query().Find(tx, &CodeGenTemplate{}, u.Query)
+		if err != nil {
+			return res, app.Error().New(http.StatusInternalServerError, err.Error())
+		}
+		res.SetData(data, u.Query)
+		return res, nil
+	})
+	return res, err
+}
+
+// Export returns the function to write all codegentemplate data (filtered, sorted and selected like Get) to w with the export format.
+// The permission is validated before the function is returned, so the error can be responded before the data is streamed.
+func (u useCase) Export(format string) (func(w io.Writer) error, error) {
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.export")
+	if err != nil {
+		return nil, err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return nil, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	return func(w io.Writer) error {
+		return app.Query().Export(w, format, u.Ctx.Lang, tx, func() app.ModelInterface { return &CodeGenTemplate{} }, u.Query)
+	}, nil
+}
+
+// GetHistory returns the list of activity log of the codegentemplate data for the specified ID.
+func (u useCase) GetHistory(id string) (app.ListModel, error) {
+	res := app.ListModel{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.history")
+	if err != nil {
 		return res, err
 	}
 
@@ -65,33 +179,40 @@ func (u useCase) GetByID(id string) (CodeGenTemplate, error) {
 		return res, app.Error().New(http.StatusInternalServerError, err.Error())
 	}
 
-	// get from db
-	u.Query.Add("id", realID.String)
-	err = app.Query().First(tx, &res, u.Query)
+	// set pagination info
+	u.Query.Set("entity", CodeGenTemplate{}.EndPoint())
+	u.Query.Set("record_id", id)
+	res.Count,
+		res.PageContext.Page,
+		res.PageContext.PerPage,
+		res.PageContext.PageCount,
+		err = app.Query().PaginationInfo(tx, &app.ActivityLog{}, u.Query)
 	if err != nil {
-		return res, u.Ctx.NotFoundError(err, CodeGenTemplate{}.EndPoint(), key, id)
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	// return data count if $per_page set to 0
+	if res.PageContext.PerPage == 0 {
+		return res, err
 	}
 
-	// save to cache and return if exists
-	app.Cache().Set(cacheKey, res)
+	// find data
+	data, err := app.Query().Find(tx, &app.ActivityLog{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	res.SetData(data, u.Query)
 	return res, err
 }
 
-// Get returns the list of codegentemplate data.
-func (u useCase) Get() (app.ListModel, error) {
+// GetTrash returns the list of deleted codegentemplate data which is not purged yet.
+func (u useCase) GetTrash() (app.ListModel, error) {
 	res := app.ListModel{}
 
 	// check permission
-	err := u.Ctx.ValidatePermission("end_point.list")
+	err := u.Ctx.ValidatePermission("end_point.trash")
 	if err != nil {
 		return res, err
 	}
-	// get from cache and return if exists
-	cacheKey := CodeGenTemplate{}.EndPoint() + "?" + u.Query.Encode()
-	err = app.Cache().Get(cacheKey, &res)
-	if err == nil {
-		return res, err
-	}
 
 	// prepare db for current ctx
 	tx, err := u.Ctx.DB()
@@ -104,7 +225,7 @@ func (u useCase) Get() (app.ListModel, error) {
 		res.PageContext.Page,
 		res.PageContext.PerPage,
 		res.PageContext.PageCount,
-		err = app.Query().PaginationInfo(tx, &CodeGenTemplate{}, u.Query)
+		err = app.Query().PaginationInfo(tx, &CodeGenTemplate{IsTrash: true}, u.Query)
 	if err != nil {
 		return res, app.Error().New(http.StatusInternalServerError, err.Error())
 	}
@@ -114,14 +235,11 @@ func (u useCase) Get() (app.ListModel, error) {
 	}
 
 	// find data
-	data, err := app.Query().Find(tx, &CodeGenTemplate{}, u.Query)
+	data, err := app.Query().Find(tx, &CodeGenTemplate{IsTrash: true}, u.Query)
 	if err != nil {
 		return res, app.Error().New(http.StatusInternalServerError, err.Error())
 	}
 	res.SetData(data, u.Query)
-
-	// save to cache and return if exists
-	app.Cache().Set(cacheKey, res)
 	return res, err
 }
 
@@ -160,11 +278,16 @@ func (u useCase) Create(param *CodeGenTemplate, paramCreate *ParamCreate) error
 	}
 
 	// invalidate cache
-	app.Cache().Invalidate(CodeGenTemplate{}.EndPoint())
+	app.Cache().Invalidate(*u.Ctx, CodeGenTemplate{}.EndPoint())
 
-	// save history (user activity), send webhook, etc
-	go u.Ctx.Hook("POST", "create", param.ID.String, param)
-	return nil
+	// get the created data as it is written by the db transaction
+	current, err := u.getCurrent(param.ID.String)
+	if err != nil {
+		return err
+	}
+
+	// save history (user activity), send webhook, etc on the background job after the db transaction is committed
+	return u.Ctx.EnqueueHook(CodeGenTemplate{}.EndPoint(), "POST", "create", param.ID.String, nil, current)
 }
 
 // UpdateByID updates the codegentemplate data for the specified ID with specified parameters.
@@ -182,8 +305,14 @@ func (u useCase) UpdateByID(id string, param *CodeGenTemplate, paramUpdate *Para
 		return err
 	}
 
-	// get previous data
-	old, err := u.GetByID(id)
+	// get previous data from the db instead of the cache, its updated_at is the condition of the change below
+	old, err := u.getPrevious(id)
+	if err != nil {
+		return err
+	}
+
+	// validate the data is not changed by another user since the client read it
+	err = u.Ctx.ValidateETag(app.ETag(old.ID.String, old.UpdatedAt))
 	if err != nil {
 		return err
 	}
@@ -199,21 +328,30 @@ func (u useCase) UpdateByID(id string, param *CodeGenTemplate, paramUpdate *Para
 		return app.Error().New(http.StatusInternalServerError, err.Error())
 	}
 
-	// update data on the db
-	err = tx.Model(param).Where("id = ?", old.ID).Updates(param).Error
-	if err != nil {
-		return app.Error().New(http.StatusInternalServerError, err.Error())
+	// update data on the db, only if it is not changed by another request since it is read
+	res := app.WhereNotModified(tx.Model(param).Where("id = ?", old.ID), old.UpdatedAt).Updates(param)
+	if res.Error != nil {
+		return app.Error().New(http.StatusInternalServerError, res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return u.Ctx.PreconditionFailedError()
 	}
 
 	// invalidate cache
-	app.Cache().Invalidate(CodeGenTemplate{}.EndPoint(), old.ID.String)
+	app.Cache().Invalidate(*u.Ctx, CodeGenTemplate{}.EndPoint(), old.ID.String)
 
-	// save history (user activity), send webhook, etc
-	go u.Ctx.Hook("PUT", paramUpdate.Reason.String, old.ID.String, old)
-	return nil
+	// get the updated data as it is written by the db transaction
+	current, err := u.getCurrent(old.ID.String)
+	if err != nil {
+		return err
+	}
+
+	// save history (user activity), send webhook, etc on the background job after the db transaction is committed
+	return u.Ctx.EnqueueHook(CodeGenTemplate{}.EndPoint(), "PUT", paramUpdate.Reason.String, old.ID.String, old, current)
 }
 
-// PartiallyUpdateByID updates the codegentemplate data for the specified ID with specified parameters.
+// PartiallyUpdateByID updates the codegentemplate data for the specified ID with specified parameters,
+// using JSON Merge Patch (RFC 7396) semantics when paramUpdate.Fields is set.
 func (u useCase) PartiallyUpdateByID(id string, param *CodeGenTemplate, paramUpdate *ParamPartiallyUpdate) error {
 
 	// check permission
@@ -228,8 +366,14 @@ func (u useCase) PartiallyUpdateByID(id string, param *CodeGenTemplate, paramUpd
 		return err
 	}
 
-	// get previous data
-	old, err := u.GetByID(id)
+	// get previous data from the db instead of the cache, its updated_at is the condition of the change below
+	old, err := u.getPrevious(id)
+	if err != nil {
+		return err
+	}
+
+	// validate the data is not changed by another user since the client read it
+	err = u.Ctx.ValidateETag(app.ETag(old.ID.String, old.UpdatedAt))
 	if err != nil {
 		return err
 	}
@@ -245,18 +389,31 @@ func (u useCase) PartiallyUpdateByID(id string, param *CodeGenTemplate, paramUpd
 		return app.Error().New(http.StatusInternalServerError, err.Error())
 	}
 
-	// update data on the db
-	err = tx.Model(param).Where("id = ?", old.ID).Updates(param).Error
-	if err != nil {
-		return app.Error().New(http.StatusInternalServerError, err.Error())
+	// update data on the db, only the fields on the request body are updated (merge patch),
+	// so the field can be set to null and the absent field is not changed, only if it is not changed by another request since it is read
+	query := app.WhereNotModified(tx.Model(param).Where("id = ?", old.ID), old.UpdatedAt)
+	if paramUpdate.Fields != nil {
+		query = query.Select(append(app.Query().Columns(param, paramUpdate.Fields), "updated_at"))
+	}
+	res := query.Updates(param)
+	if res.Error != nil {
+		return app.Error().New(http.StatusInternalServerError, res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return u.Ctx.PreconditionFailedError()
 	}
 
 	// invalidate cache
-	app.Cache().Invalidate(CodeGenTemplate{}.EndPoint(), old.ID.String)
+	app.Cache().Invalidate(*u.Ctx, CodeGenTemplate{}.EndPoint(), old.ID.String)
 
-	// save history (user activity), send webhook, etc
-	go u.Ctx.Hook("PATCH", paramUpdate.Reason.String, old.ID.String, old)
-	return nil
+	// get the updated data as it is written by the db transaction
+	current, err := u.getCurrent(old.ID.String)
+	if err != nil {
+		return err
+	}
+
+	// save history (user activity), send webhook, etc on the background job after the db transaction is committed
+	return u.Ctx.EnqueueHook(CodeGenTemplate{}.EndPoint(), "PATCH", paramUpdate.Reason.String, old.ID.String, old, current)
 }
 
 // DeleteByID deletes the codegentemplate data for the specified ID.
@@ -274,8 +431,14 @@ func (u useCase) DeleteByID(id string, paramDelete *ParamDelete) error {
 		return err
 	}
 
-	// get previous data
-	old, err := u.GetByID(id)
+	// get previous data from the db instead of the cache, its updated_at is the condition of the change below
+	old, err := u.getPrevious(id)
+	if err != nil {
+		return err
+	}
+
+	// validate the data is not changed by another user since the client read it
+	err = u.Ctx.ValidateETag(app.ETag(old.ID.String, old.UpdatedAt))
 	if err != nil {
 		return err
 	}
@@ -286,23 +449,214 @@ func (u useCase) DeleteByID(id string, paramDelete *ParamDelete) error {
 		return app.Error().New(http.StatusInternalServerError, err.Error())
 	}
 
+	// update data on the db, only if it is not changed by another request since it is read
+	res := app.WhereNotModified(tx.Model(paramDelete).Where("id = ?", old.ID), old.UpdatedAt).Update("deleted_at", time.Now().UTC())
+	if res.Error != nil {
+		return app.Error().New(http.StatusInternalServerError, res.Error.Error())
+	}
+	if res.RowsAffected == 0 {
+		return u.Ctx.PreconditionFailedError()
+	}
+
+	// invalidate cache
+	app.Cache().Invalidate(*u.Ctx, CodeGenTemplate{}.EndPoint(), old.ID.String)
+
+	// save history (user activity), send webhook, etc on the background job after the db transaction is committed
+	return u.Ctx.EnqueueHook(CodeGenTemplate{}.EndPoint(), "DELETE", paramDelete.Reason.String, old.ID.String, old, nil)
+}
+
+// RestoreByID restores the deleted codegentemplate data for the specified ID from the trash.
+func (u useCase) RestoreByID(id string, paramRestore *ParamRestore) error {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.restore")
+	if err != nil {
+		return err
+	}
+
+	// validate param
+	err = u.Ctx.ValidateParam(paramRestore)
+	if err != nil {
+		return err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// get deleted data
+	old := CodeGenTemplate{IsTrash: true}
+	u.Query.Add("id", id)
+	err = app.Query().First(tx, &old, u.Query)
+	if err != nil {
+		return u.Ctx.NotFoundError(err, CodeGenTemplate{}.EndPoint(), "id", id)
+	}
+
 	// update data on the db
-	if err = tx.Model(paramDelete).Where("id = ?", old.ID).Update("deleted_at", time.Now().UTC()).Error; err != nil {
+	err = tx.Model(&CodeGenTemplate{}).Where("id = ?", old.ID).Updates(map[string]any{
+		"deleted_at": nil,
+		"updated_at": time.Now().UTC(),
+	}).Error
+	if err != nil {
 		return app.Error().New(http.StatusInternalServerError, err.Error())
 	}
 
 	// invalidate cache
-	app.Cache().Invalidate(CodeGenTemplate{}.EndPoint(), old.ID.String)
+	app.Cache().Invalidate(*u.Ctx, CodeGenTemplate{}.EndPoint(), old.ID.String)
 
-	// save history (user activity), send webhook, etc
-	go u.Ctx.Hook("DELETE", paramDelete.Reason.String, old.ID.String, old)
-	return nil
+	// get the restored data as it is written by the db transaction
+	current, err := u.getCurrent(old.ID.String)
+	if err != nil {
+		return err
+	}
+
+	// save history (user activity), send webhook, etc on the background job after the db transaction is committed
+	return u.Ctx.EnqueueHook(CodeGenTemplate{}.EndPoint(), "RESTORE", paramRestore.Reason.String, old.ID.String, old, current)
+}
+
+// PurgeTrash permanently deletes the codegentemplate data which is deleted more than CodeGenTemplate.TrashRetentionDays ago.
+// It returns the number of purged data.
+func (u useCase) PurgeTrash() (int64, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.purge")
+	if err != nil {
+		return 0, err
+	}
+
+	// keep the deleted data forever if the retention is not set
+	days := CodeGenTemplate{}.TrashRetentionDays()
+	if days <= 0 {
+		return 0, nil
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return 0, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// delete data from the db
+	res := tx.Where("deleted_at < ?", time.Now().UTC().AddDate(0, 0, -days)).Delete(&CodeGenTemplate{})
+	if res.Error != nil {
+		return 0, app.Error().New(http.StatusInternalServerError, res.Error.Error())
+	}
+	return res.RowsAffected, nil
+}
+
+// BulkCreate creates many codegentemplate data in one request, each item is the parameters of Create.
+func (u useCase) BulkCreate(param app.BulkParam) (app.BulkResult, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.create")
+	if err != nil {
+		return app.BulkResult{}, err
+	}
+
+	return u.Ctx.RunBulk(param, createItem)
+}
+
+// Import creates many codegentemplate data from the uploaded csv or xlsx file, each row is the parameters of create codegentemplate.
+// The large file is imported on the background by ImportJob, the progress can be polled on `GET /api/imports/{id}`.
+func (u useCase) Import(file *multipart.FileHeader, param app.ImportParam) (app.ImportRecord, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.import")
+	if err != nil {
+		return app.ImportRecord{}, err
+	}
+
+	return u.Ctx.Import(CodeGenTemplate{}.EndPoint(), file, param, ParamCreate{}, createItem)
+}
+
+// ImportJob is the handler of the "end_point.import" job, it is registered on src/worker.go.
+func ImportJob(ctx app.Ctx, p app.ImportPayload) error {
+	return ctx.RunImportJob(p, createItem)
+}
+
+// createItem creates codegentemplate data from the bulk or import item.
+func createItem(ctx app.Ctx, item []byte) (string, error) {
+	p := &CodeGenTemplate{}
+	paramCreate := &ParamCreate{}
+	if err := app.Query().BindJSON(item, p, paramCreate); err != nil {
+		return "", app.Error().New(http.StatusBadRequest, err.Error())
+	}
+	err := UseCase(ctx).Create(p, paramCreate)
+	return p.ID.String, err
+}
+
+// BulkPartiallyUpdate partially updates many codegentemplate data in one request,
+// each item is the parameters of PartiallyUpdateByID with the id.
+func (u useCase) BulkPartiallyUpdate(param app.BulkParam) (app.BulkResult, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.edit")
+	if err != nil {
+		return app.BulkResult{}, err
+	}
+
+	return u.Ctx.RunBulk(param, func(ctx app.Ctx, item []byte) (string, error) {
+		p := &CodeGenTemplate{}
+		paramUpdate := &ParamPartiallyUpdate{}
+		if err := app.Query().BindJSON(item, p, paramUpdate); err != nil {
+			return "", app.Error().New(http.StatusBadRequest, err.Error())
+		}
+		fields, err := app.Query().JSONKeys(item)
+		if err != nil {
+			return "", app.Error().New(http.StatusBadRequest, err.Error())
+		}
+		paramUpdate.Fields = fields
+		id := paramUpdate.ID.String
+		if id == "" {
+			return "", app.Error().New(http.StatusBadRequest, ctx.Trans("id_required"))
+		}
+		return id, UseCase(ctx).PartiallyUpdateByID(id, p, paramUpdate)
+	})
+}
+
+// BulkDelete deletes many codegentemplate data in one request, each item is the parameters of DeleteByID with the id.
+func (u useCase) BulkDelete(param app.BulkParam) (app.BulkResult, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("end_point.delete")
+	if err != nil {
+		return app.BulkResult{}, err
+	}
+
+	return u.Ctx.RunBulk(param, func(ctx app.Ctx, item []byte) (string, error) {
+		paramDelete := &ParamDelete{}
+		if err := app.Query().BindJSON(item, paramDelete); err != nil {
+			return "", app.Error().New(http.StatusBadRequest, err.Error())
+		}
+		id := paramDelete.ID.String
+		if id == "" {
+			return "", app.Error().New(http.StatusBadRequest, ctx.Trans("id_required"))
+		}
+		return id, UseCase(ctx).DeleteByID(id, paramDelete)
+	})
+}
+
+// getPrevious returns the codegentemplate data for the specified ID from the db instead of the cache, before it is changed.
+func (u useCase) getPrevious(id string) (CodeGenTemplate, error) {
+	ctx := *u.Ctx
+	ctx.IsNoCache = true
+	return UseCase(ctx, u.Query).GetByID(id)
+}
+
+// getCurrent returns the codegentemplate data for the specified ID from the db instead of the cache,
+// it is the new value of the hook as it is written by the db transaction of current ctx.
+func (u useCase) getCurrent(id string) (CodeGenTemplate, error) {
+	ctx := *u.Ctx
+	ctx.IsNoCache = true
+	return UseCase(ctx).GetByID(id)
 }
 
 // GetIDByKey get codegentemplate id by unique key.
 func (u useCase) GetIDByKey(key, val string) (app.NullUUID, error) {
 	d := &CodeGenTemplate{}
-	app.Cache().Get(CodeGenTemplate{}.EndPoint()+"."+val, d)
+	app.Cache().GetFetched(CodeGenTemplate{}.EndPoint()+"."+val, d)
 	if d.ID.String != "" {
 		return d.ID, nil
 	}
@@ -331,10 +685,13 @@ func (u useCase) ParseParamCreate(data CodeGenTemplate) *ParamCreate {
 
 // setDefaultValue set default value of undefined field when create or update codegentemplate data.
 func (u useCase) setDefaultValue(old CodeGenTemplate, new *CodeGenTemplate) error {
+	now := app.NewNullDateTime(time.Now().UTC())
 	if !old.ID.Valid {
 		new.ID = app.NewNullUUID()
+		new.CreatedAt = now
 	} else {
 		new.ID = old.ID
 	}
+	new.UpdatedAt = now
 	return nil
 }
@@ -0,0 +1,2 @@
+// imports is a package to get the progress and the error report of the csv and xlsx import.
+package imports
@@ -0,0 +1,55 @@
+package imports
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// REST returns a *restAPI.
+func REST() *restAPI {
+	return &restAPI{}
+}
+
+// restAPI provides a convenient interface for imports REST API handler.
+type restAPI struct {
+	UseCase useCase
+}
+
+// injectDeps inject the dependencies of the imports REST API handler.
+func (r *restAPI) injectDeps(c *fiber.Ctx) error {
+	ctx, ok := c.Locals(app.CtxKey).(*app.Ctx)
+	if !ok {
+		return app.Error().New(http.StatusInternalServerError, "ctx is not found")
+	}
+	r.UseCase = UseCase(*ctx, app.Query().Parse(c.OriginalURL()))
+	return nil
+}
+
+// Get is the REST API handler for `GET /api/imports`.
+func (r *restAPI) Get(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.Get()
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	res.SetLink(c)
+	model := &app.ImportRecord{}
+	return c.JSON(app.Query().Return(res, model.IsFlat()))
+}
+
+// GetByID is the REST API handler for `GET /api/imports/{id}`.
+func (r *restAPI) GetByID(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetByID(c.Params("id"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.JSON(app.Query().Return(res, res.IsFlat()))
+}
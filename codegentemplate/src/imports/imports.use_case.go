@@ -0,0 +1,92 @@
+package imports
+
+import (
+	"net/http"
+	"net/url"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// UseCase returns a useCase for expected use case functional.
+func UseCase(ctx app.Ctx, query ...url.Values) useCase {
+	u := useCase{
+		Ctx:   &ctx,
+		Query: url.Values{},
+	}
+	if len(query) > 0 {
+		u.Query = query[0]
+	}
+	return u
+}
+
+// useCase provides a convenient interface for imports use case, use UseCase to access useCase.
+type useCase struct {
+
+	// injectable dependencies
+	Ctx   *app.Ctx   `json:"-" db:"-" gorm:"-"`
+	Query url.Values `json:"-" db:"-" gorm:"-"`
+}
+
+// Get returns the list of import.
+func (u useCase) Get() (app.ListModel, error) {
+	res := app.ListModel{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("imports.list")
+	if err != nil {
+		return res, err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// set pagination info
+	res.Count,
+		res.PageContext.Page,
+		res.PageContext.PerPage,
+		res.PageContext.PageCount,
+		err = app.Query().PaginationInfo(tx, &app.ImportRecord{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	// return data count if $per_page set to 0
+	if res.PageContext.PerPage == 0 {
+		return res, err
+	}
+
+	// find data
+	data, err := app.Query().Find(tx, &app.ImportRecord{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	res.SetData(data, u.Query)
+	return res, err
+}
+
+// GetByID returns the import for the specified ID, including the progress and the error report.
+func (u useCase) GetByID(id string) (app.ImportRecord, error) {
+	res := app.ImportRecord{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("imports.detail")
+	if err != nil {
+		return res, err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// get from db
+	u.Query.Add("id", id)
+	err = app.Query().First(tx, &res, u.Query)
+	if err != nil {
+		return res, u.Ctx.NotFoundError(err, app.ImportRecord{}.EndPoint(), "id", id)
+	}
+	return res, nil
+}
@@ -0,0 +1,2 @@
+// logstats is a package to inspect the statistics of the log queue, for example the dropped and the sampled out log entries.
+package logstats
@@ -0,0 +1,38 @@
+package logstats
+
+import (
+	"net/url"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// UseCase returns a useCase for expected use case functional.
+func UseCase(ctx app.Ctx, query ...url.Values) useCase {
+	u := useCase{
+		Ctx:   &ctx,
+		Query: url.Values{},
+	}
+	if len(query) > 0 {
+		u.Query = query[0]
+	}
+	return u
+}
+
+// useCase provides a convenient interface for log stats use case, use UseCase to access useCase.
+type useCase struct {
+
+	// injectable dependencies
+	Ctx   *app.Ctx   `json:"-" db:"-" gorm:"-"`
+	Query url.Values `json:"-" db:"-" gorm:"-"`
+}
+
+// Get returns the statistics of the log queue of this instance.
+func (u useCase) Get() (app.LogStats, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("logs.stats")
+	if err != nil {
+		return app.LogStats{}, err
+	}
+	return app.Logger().Stats(), nil
+}
@@ -22,6 +22,7 @@ type middlewareUtil struct {
 
 func (*middlewareUtil) Configure() {
 	app.Server().AddMiddleware(middleware.Ctx().New)
+	app.Server().AddMiddleware(middleware.Idempotency().New)
 	app.Server().AddMiddleware(middleware.DB().New)
 	app.Server().AddMiddleware(middleware.Log().New)
 }
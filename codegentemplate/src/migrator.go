@@ -26,6 +26,14 @@ type migratorUtil struct {
 }
 
 func (*migratorUtil) Configure() {
+	app.DB().RegisterTable("main", app.ActivityLog{})
+	app.DB().RegisterTable("main", app.CacheInvalidation{})
+	app.DB().RegisterTable("main", app.ImportRecord{})
+	app.DB().RegisterTable("main", app.JobRecord{})
+	app.DB().RegisterTable("main", app.ScheduleRun{})
+	app.DB().RegisterTable("main", app.ScheduleLock{})
+	app.DB().RegisterTable("main", app.WebhookSubscription{})
+	app.DB().RegisterTable("main", app.WebhookDelivery{})
 	// RegisterTable : DONT REMOVE THIS COMMENT
 }
 
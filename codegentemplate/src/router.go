@@ -2,6 +2,11 @@ package src
 
 import (
 	"grest.dev/cmd/codegentemplate/app"
+	"grest.dev/cmd/codegentemplate/src/cache"
+	"grest.dev/cmd/codegentemplate/src/imports"
+	"grest.dev/cmd/codegentemplate/src/logstats"
+	"grest.dev/cmd/codegentemplate/src/schedule"
+	"grest.dev/cmd/codegentemplate/src/webhook"
 	// import : DONT REMOVE THIS COMMENT
 )
 
@@ -23,5 +28,25 @@ type routerUtil struct {
 func (r *routerUtil) Configure() {
 	app.Server().AddRoute("/api/version", "GET", app.Server().Version, nil)
 
+	app.Server().AddRoute("/api/webhooks", "POST", webhook.REST().Create, webhook.OpenAPI().Create())
+	app.Server().AddRoute("/api/webhooks", "GET", webhook.REST().Get, webhook.OpenAPI().Get())
+	app.Server().AddRoute("/api/webhooks/{id}", "GET", webhook.REST().GetByID, webhook.OpenAPI().GetByID())
+	app.Server().AddRoute("/api/webhooks/{id}", "PUT", webhook.REST().UpdateByID, webhook.OpenAPI().UpdateByID())
+	app.Server().AddRoute("/api/webhooks/{id}", "DELETE", webhook.REST().DeleteByID, webhook.OpenAPI().DeleteByID())
+	app.Server().AddRoute("/api/webhooks/{id}/deliveries", "GET", webhook.REST().GetDeliveries, webhook.OpenAPI().GetDeliveries())
+	app.Server().AddRoute("/api/webhooks/{id}/deliveries/{delivery_id}/redeliver", "POST", webhook.REST().Redeliver, webhook.OpenAPI().Redeliver())
+
+	app.Server().AddRoute("/api/schedules", "GET", schedule.REST().Get, schedule.OpenAPI().Get())
+	app.Server().AddRoute("/api/schedules/{name}/runs", "GET", schedule.REST().GetRuns, schedule.OpenAPI().GetRuns())
+	app.Server().AddRoute("/api/schedules/{name}/run", "POST", schedule.REST().Run, schedule.OpenAPI().Run())
+
+	app.Server().AddRoute("/api/imports", "GET", imports.REST().Get, imports.OpenAPI().Get())
+	app.Server().AddRoute("/api/imports/{id}", "GET", imports.REST().GetByID, imports.OpenAPI().GetByID())
+
+	app.Server().AddRoute("/api/cache", "GET", cache.REST().Get, cache.OpenAPI().Get())
+	app.Server().AddRoute("/api/cache", "DELETE", cache.REST().Flush, cache.OpenAPI().Flush())
+
+	app.Server().AddRoute("/api/logs/stats", "GET", logstats.REST().Get, logstats.OpenAPI().Get())
+
 	// AddRoute : DONT REMOVE THIS COMMENT
 }
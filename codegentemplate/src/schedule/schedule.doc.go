@@ -0,0 +1,2 @@
+// schedule is a package to list the scheduled tasks, get the run history and trigger the task manually.
+package schedule
@@ -0,0 +1,82 @@
+package schedule
+
+import "grest.dev/cmd/codegentemplate/app"
+
+// OpenAPI is constructor for *openAPI, to autogenerate open api document.
+func OpenAPI() *OpenAPIOperation {
+	return &OpenAPIOperation{}
+}
+
+// OpenAPIOperation embed from app.OpenAPIOperation for simplicity, used for autogenerate open api document.
+type OpenAPIOperation struct {
+	app.OpenAPIOperation
+}
+
+// Base is common detail of schedules open api document component.
+func (o *OpenAPIOperation) Base() {
+	o.Tags = []string{"Schedule"}
+	o.HeaderParams = []map[string]any{{"$ref": "#/components/parameters/headerParam.Accept-Language"}}
+	o.Responses = map[string]map[string]any{
+		"200": {
+			"description": "Success",
+			"content":     map[string]any{"application/json": &app.ScheduledTaskList{}},
+		},
+		"400": app.OpenAPIError().BadRequest(),
+		"401": app.OpenAPIError().Unauthorized(),
+		"403": app.OpenAPIError().Forbidden(),
+	}
+	o.Securities = []map[string][]string{}
+}
+
+// Get is detail of `GET /api/schedules` open api document component.
+func (o *OpenAPIOperation) Get() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get Scheduled Task"
+	o.Description = "Use this method to get list of scheduled task with the next run and the last run"
+	return o
+}
+
+// GetRuns is detail of `GET /api/schedules/{name}/runs` open api document component.
+func (o *OpenAPIOperation) GetRuns() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get Scheduled Task Runs"
+	o.Description = "Use this method to get the run history of scheduled task by name"
+	o.PathParams = []map[string]any{
+		{"in": "path", "name": "name", "description": "A name of the scheduled task", "schema": map[string]any{"type": "string"}, "required": true},
+	}
+	o.QueryParams = []map[string]any{{"$ref": "#/components/parameters/queryParam.Any"}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.ScheduleRunList{}},
+	}
+	return o
+}
+
+// Run is detail of `POST /api/schedules/{name}/run` open api document component.
+func (o *OpenAPIOperation) Run() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Run Scheduled Task"
+	o.Description = "Use this method to run the scheduled task by name manually on the background"
+	o.PathParams = []map[string]any{
+		{"in": "path", "name": "name", "description": "A name of the scheduled task", "schema": map[string]any{"type": "string"}, "required": true},
+	}
+	delete(o.Responses, "200")
+	o.Responses["202"] = map[string]any{
+		"description": "Accepted",
+		"content":     map[string]any{"application/json": &app.ScheduleRun{}},
+	}
+	o.Responses["409"] = map[string]any{"description": "The scheduled task is running"}
+	return o
+}
@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// REST returns a *restAPI.
+func REST() *restAPI {
+	return &restAPI{}
+}
+
+// restAPI provides a convenient interface for schedule REST API handler.
+type restAPI struct {
+	UseCase useCase
+}
+
+// injectDeps inject the dependencies of the schedule REST API handler.
+func (r *restAPI) injectDeps(c *fiber.Ctx) error {
+	ctx, ok := c.Locals(app.CtxKey).(*app.Ctx)
+	if !ok {
+		return app.Error().New(http.StatusInternalServerError, "ctx is not found")
+	}
+	r.UseCase = UseCase(*ctx, app.Query().Parse(c.OriginalURL()))
+	return nil
+}
+
+// Get is the REST API handler for `GET /api/schedules`.
+func (r *restAPI) Get(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.Get()
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.JSON(res)
+}
+
+// GetRuns is the REST API handler for `GET /api/schedules/{name}/runs`.
+func (r *restAPI) GetRuns(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetRuns(c.Params("name"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	res.SetLink(c)
+	model := &app.ScheduleRun{}
+	return c.JSON(app.Query().Return(res, model.IsFlat()))
+}
+
+// Run is the REST API handler for `POST /api/schedules/{name}/run`.
+func (r *restAPI) Run(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.Run(c.Params("name"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.Status(http.StatusAccepted).JSON(app.Query().Return(res, res.IsFlat()))
+}
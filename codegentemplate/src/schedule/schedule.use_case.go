@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"net/http"
+	"net/url"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// UseCase returns a useCase for expected use case functional.
+func UseCase(ctx app.Ctx, query ...url.Values) useCase {
+	u := useCase{
+		Ctx:   &ctx,
+		Query: url.Values{},
+	}
+	if len(query) > 0 {
+		u.Query = query[0]
+	}
+	return u
+}
+
+// useCase provides a convenient interface for schedule use case, use UseCase to access useCase.
+type useCase struct {
+
+	// injectable dependencies
+	Ctx   *app.Ctx   `json:"-" db:"-" gorm:"-"`
+	Query url.Values `json:"-" db:"-" gorm:"-"`
+}
+
+// Get returns the list of scheduled task.
+func (u useCase) Get() (app.ScheduledTaskList, error) {
+	res := app.ScheduledTaskList{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("schedules.list")
+	if err != nil {
+		return res, err
+	}
+	res.Data = app.Schedule().Tasks()
+	return res, nil
+}
+
+// GetRuns returns the run history of the scheduled task for the specified name.
+func (u useCase) GetRuns(name string) (app.ListModel, error) {
+	res := app.ListModel{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("schedules.runs")
+	if err != nil {
+		return res, err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// set pagination info
+	u.Query.Set("name", name)
+	res.Count,
+		res.PageContext.Page,
+		res.PageContext.PerPage,
+		res.PageContext.PageCount,
+		err = app.Query().PaginationInfo(tx, &app.ScheduleRun{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	// return data count if $per_page set to 0
+	if res.PageContext.PerPage == 0 {
+		return res, err
+	}
+
+	// find data
+	data, err := app.Query().Find(tx, &app.ScheduleRun{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	res.SetData(data, u.Query)
+	return res, err
+}
+
+// Run runs the scheduled task for the specified name manually on the background.
+func (u useCase) Run(name string) (app.ScheduleRun, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("schedules.run")
+	if err != nil {
+		return app.ScheduleRun{}, err
+	}
+	return app.Schedule().Trigger(*u.Ctx, name)
+}
@@ -1,17 +1,17 @@
 package src
 
 import (
-	"github.com/robfig/cron/v3"
+	"context"
+	"log/slog"
 
 	"grest.dev/cmd/codegentemplate/app"
+	// import : DONT REMOVE THIS COMMENT
 )
 
 func Scheduler() *schedulerUtil {
 	if scheduler == nil {
 		scheduler = &schedulerUtil{}
-		if app.APP_ENV == "local" || app.IS_MAIN_SERVER {
-			scheduler.Configure()
-		}
+		scheduler.Configure()
 		scheduler.isConfigured = true
 	}
 	return scheduler
@@ -23,11 +23,25 @@ type schedulerUtil struct {
 	isConfigured bool
 }
 
+// Configure registers the scheduled tasks and starts the scheduler.
+// The scheduler runs on every instance, each run is guarded by the distributed lock so the task is only run once.
 func (s *schedulerUtil) Configure() {
-	c := cron.New()
+	// add scheduled task here, for example :
+	// s.add("invoices.send_reminder", "CRON_TZ=Asia/Jakarta 5 0 * * *", invoice.SendReminder)
 
-	// add scheduler func here, for example :
-	// c.AddFunc("CRON_TZ=Asia/Jakarta 5 0 * * *", app.Auth().RemoveExpiredToken)
+	s.add("jobs.purge", "0 3 * * *", app.Job().PurgeTask)
+	// AddSchedule : DONT REMOVE THIS COMMENT
 
-	c.Start()
+	app.Schedule().Start()
+}
+
+// Stop stops the scheduler and waits for running tasks to complete or until ctx is done.
+func (s *schedulerUtil) Stop(ctx context.Context) {
+	app.Schedule().Stop(ctx)
+}
+
+func (s *schedulerUtil) add(name, spec string, fn func(ctx app.Ctx) error) {
+	if err := app.Schedule().Add(name, spec, fn); err != nil {
+		app.Logger().Error("Failed to add scheduled task", slog.String("task", name), slog.Any("err", err))
+	}
 }
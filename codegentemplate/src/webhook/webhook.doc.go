@@ -0,0 +1,2 @@
+// webhook is a package to manage the webhook subscriptions and the delivery log.
+package webhook
@@ -0,0 +1,18 @@
+package webhook
+
+import "grest.dev/cmd/codegentemplate/app"
+
+// ParamCreate is the expected parameters for create a new webhook subscription.
+type ParamCreate struct {
+	app.WebhookSubscription
+}
+
+// ParamUpdate is the expected parameters for update the webhook subscription.
+type ParamUpdate struct {
+	app.WebhookSubscription
+}
+
+// ParamDelete is the expected parameters for delete the webhook subscription.
+type ParamDelete struct {
+	app.WebhookSubscription
+}
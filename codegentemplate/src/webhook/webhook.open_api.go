@@ -0,0 +1,140 @@
+package webhook
+
+import "grest.dev/cmd/codegentemplate/app"
+
+// OpenAPI is constructor for *openAPI, to autogenerate open api document.
+func OpenAPI() *OpenAPIOperation {
+	return &OpenAPIOperation{}
+}
+
+// OpenAPIOperation embed from app.OpenAPIOperation for simplicity, used for autogenerate open api document.
+type OpenAPIOperation struct {
+	app.OpenAPIOperation
+}
+
+// Base is common detail of webhooks open api document component.
+func (o *OpenAPIOperation) Base() {
+	o.Tags = []string{"Webhook"}
+	o.HeaderParams = []map[string]any{{"$ref": "#/components/parameters/headerParam.Accept-Language"}}
+	o.Responses = map[string]map[string]any{
+		"200": {
+			"description": "Success",
+			"content":     map[string]any{"application/json": &app.WebhookSubscription{}}, // will auto create schema $ref: '#/components/schemas/WebhookSubscription' if not exists
+		},
+		"400": app.OpenAPIError().BadRequest(),
+		"401": app.OpenAPIError().Unauthorized(),
+		"403": app.OpenAPIError().Forbidden(),
+	}
+	o.Securities = []map[string][]string{}
+}
+
+// Get is detail of `GET /api/webhooks` open api document component.
+func (o *OpenAPIOperation) Get() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get Webhook"
+	o.Description = "Use this method to get list of webhook subscription"
+	o.QueryParams = []map[string]any{{"$ref": "#/components/parameters/queryParam.Any"}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.WebhookSubscriptionList{}},
+	}
+	return o
+}
+
+// GetByID is detail of `GET /api/webhooks/{id}` open api document component.
+func (o *OpenAPIOperation) GetByID() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get Webhook By ID"
+	o.Description = "Use this method to get webhook subscription by id"
+	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
+	return o
+}
+
+// Create is detail of `POST /api/webhooks` open api document component.
+func (o *OpenAPIOperation) Create() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Create Webhook"
+	o.Description = "Use this method to subscribe the events (for example `end_point.created`, `end_point.*` or `*`) to the url. " +
+		"Each delivery is signed with HMAC-SHA256 of `{X-Webhook-Timestamp}.{body}` using the secret in the `X-Webhook-Signature` header. " +
+		"The secret is write-only, it is only returned on this response and masked on the other responses."
+	o.Body = map[string]any{"application/json": &ParamCreate{}}
+	return o
+}
+
+// UpdateByID is detail of `PUT /api/webhooks/{id}` open api document component.
+func (o *OpenAPIOperation) UpdateByID() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Update Webhook By ID"
+	o.Description = "Use this method to update webhook subscription by id"
+	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
+	o.Body = map[string]any{"application/json": &ParamUpdate{}}
+	return o
+}
+
+// DeleteByID is detail of `DELETE /api/webhooks/{id}` open api document component.
+func (o *OpenAPIOperation) DeleteByID() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Delete Webhook By ID"
+	o.Description = "Use this method to delete webhook subscription by id"
+	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
+	return o
+}
+
+// GetDeliveries is detail of `GET /api/webhooks/{id}/deliveries` open api document component.
+func (o *OpenAPIOperation) GetDeliveries() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Get Webhook Deliveries By ID"
+	o.Description = "Use this method to get the delivery log of webhook subscription by id"
+	o.PathParams = []map[string]any{{"$ref": "#/components/parameters/pathParam.ID"}}
+	o.QueryParams = []map[string]any{{"$ref": "#/components/parameters/queryParam.Any"}}
+	o.Responses["200"] = map[string]any{
+		"description": "Success",
+		"content":     map[string]any{"application/json": &app.WebhookDeliveryList{}},
+	}
+	return o
+}
+
+// Redeliver is detail of `POST /api/webhooks/{id}/deliveries/{delivery_id}/redeliver` open api document component.
+func (o *OpenAPIOperation) Redeliver() *OpenAPIOperation {
+	if !app.IS_GENERATE_OPEN_API_DOC {
+		return o // skip for efficiency
+	}
+
+	o.Base()
+	o.Summary = "Redeliver Webhook Delivery"
+	o.Description = "Use this method to send the payload of the webhook delivery again"
+	o.PathParams = []map[string]any{
+		{"$ref": "#/components/parameters/pathParam.ID"},
+		{"in": "path", "name": "delivery_id", "description": "An ID of the webhook delivery", "schema": map[string]any{"type": "string"}, "required": true},
+	}
+	delete(o.Responses, "200")
+	o.Responses["202"] = map[string]any{
+		"description": "Accepted",
+		"content":     map[string]any{"application/json": &app.WebhookDelivery{}},
+	}
+	return o
+}
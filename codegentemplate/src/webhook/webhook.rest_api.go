@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// REST returns a *restAPI.
+func REST() *restAPI {
+	return &restAPI{}
+}
+
+// restAPI provides a convenient interface for webhook REST API handler.
+type restAPI struct {
+	UseCase useCase
+}
+
+// injectDeps inject the dependencies of the webhook REST API handler.
+func (r *restAPI) injectDeps(c *fiber.Ctx) error {
+	ctx, ok := c.Locals(app.CtxKey).(*app.Ctx)
+	if !ok {
+		return app.Error().New(http.StatusInternalServerError, "ctx is not found")
+	}
+	r.UseCase = UseCase(*ctx, app.Query().Parse(c.OriginalURL()))
+	return nil
+}
+
+// GetByID is the REST API handler for `GET /api/webhooks/{id}`.
+func (r *restAPI) GetByID(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetByID(c.Params("id"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.JSON(app.Query().Return(res, res.IsFlat()))
+}
+
+// Get is the REST API handler for `GET /api/webhooks`.
+func (r *restAPI) Get(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.Get()
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	res.SetLink(c)
+	model := &app.WebhookSubscription{}
+	return c.JSON(app.Query().Return(res, model.IsFlat()))
+}
+
+// Create is the REST API handler for `POST /api/webhooks`.
+func (r *restAPI) Create(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	param := &app.WebhookSubscription{}
+	paramCreate := &ParamCreate{}
+	if err := app.Query().BindJSON(c.Body(), param, paramCreate); err != nil {
+		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
+	}
+	if err := r.UseCase.Create(param, paramCreate); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetByID(param.ID.String)
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	res.Secret = param.Secret // the secret is only returned on the create response
+	return c.Status(http.StatusCreated).JSON(app.Query().Return(res, res.IsFlat()))
+}
+
+// UpdateByID is the REST API handler for `PUT /api/webhooks/{id}`.
+func (r *restAPI) UpdateByID(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	param := &app.WebhookSubscription{}
+	paramUpdate := &ParamUpdate{}
+	if err := app.Query().BindJSON(c.Body(), param, paramUpdate); err != nil {
+		return app.Server().Error(c, app.Error().New(http.StatusBadRequest, err.Error()))
+	}
+	if err := r.UseCase.UpdateByID(c.Params("id"), param, paramUpdate); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetByID(c.Params("id"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.JSON(app.Query().Return(res, res.IsFlat()))
+}
+
+// DeleteByID is the REST API handler for `DELETE /api/webhooks/{id}`.
+func (r *restAPI) DeleteByID(c *fiber.Ctx) error {
+	err := r.injectDeps(c)
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	id := c.Params("id")
+	if err = r.UseCase.DeleteByID(id, &ParamDelete{}); err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.JSON(r.UseCase.Ctx.Deleted(app.WebhookSubscription{}.EndPoint(), "id", id))
+}
+
+// GetDeliveries is the REST API handler for `GET /api/webhooks/{id}/deliveries`.
+func (r *restAPI) GetDeliveries(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.GetDeliveries(c.Params("id"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	res.SetLink(c)
+	model := &app.WebhookDelivery{}
+	return c.JSON(app.Query().Return(res, model.IsFlat()))
+}
+
+// Redeliver is the REST API handler for `POST /api/webhooks/{id}/deliveries/{delivery_id}/redeliver`.
+func (r *restAPI) Redeliver(c *fiber.Ctx) error {
+	if err := r.injectDeps(c); err != nil {
+		return app.Server().Error(c, err)
+	}
+	res, err := r.UseCase.Redeliver(c.Params("id"), c.Params("delivery_id"))
+	if err != nil {
+		return app.Server().Error(c, err)
+	}
+	return c.Status(http.StatusAccepted).JSON(app.Query().Return(res, res.IsFlat()))
+}
@@ -0,0 +1,263 @@
+package webhook
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"grest.dev/cmd/codegentemplate/app"
+)
+
+// UseCase returns a useCase for expected use case functional.
+func UseCase(ctx app.Ctx, query ...url.Values) useCase {
+	u := useCase{
+		Ctx:   &ctx,
+		Query: url.Values{},
+	}
+	if len(query) > 0 {
+		u.Query = query[0]
+	}
+	return u
+}
+
+// useCase provides a convenient interface for webhook use case, use UseCase to access useCase.
+type useCase struct {
+
+	// injectable dependencies
+	Ctx   *app.Ctx   `json:"-" db:"-" gorm:"-"`
+	Query url.Values `json:"-" db:"-" gorm:"-"`
+}
+
+// GetByID returns the webhook subscription for the specified ID, the secret is masked since it is write-only.
+func (u useCase) GetByID(id string) (app.WebhookSubscription, error) {
+	res := app.WebhookSubscription{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("webhooks.detail")
+	if err != nil {
+		return res, err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// get from db
+	u.Query.Add("id", id)
+	err = app.Query().First(tx, &res, u.Query)
+	if err != nil {
+		return res, u.Ctx.NotFoundError(err, app.WebhookSubscription{}.EndPoint(), "id", id)
+	}
+	if res.Secret.Valid {
+		res.Secret = app.NewNullString(app.Redacted)
+	}
+	return res, err
+}
+
+// Get returns the list of webhook subscription, the secret is masked since it is write-only.
+func (u useCase) Get() (app.ListModel, error) {
+	res := app.ListModel{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("webhooks.list")
+	if err != nil {
+		return res, err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// set pagination info
+	res.Count,
+		res.PageContext.Page,
+		res.PageContext.PerPage,
+		res.PageContext.PageCount,
+		err = app.Query().PaginationInfo(tx, &app.WebhookSubscription{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	// return data count if $per_page set to 0
+	if res.PageContext.PerPage == 0 {
+		return res, err
+	}
+
+	// find data
+	data, err := app.Query().Find(tx, &app.WebhookSubscription{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	for _, d := range data {
+		if d["secret"] != nil {
+			d["secret"] = app.Redacted
+		}
+	}
+	res.SetData(data, u.Query)
+	return res, err
+}
+
+// Create creates a new webhook subscription with specified parameters.
+func (u useCase) Create(param *app.WebhookSubscription, paramCreate *ParamCreate) error {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("webhooks.create")
+	if err != nil {
+		return err
+	}
+
+	// validate param
+	err = u.Ctx.ValidateParam(paramCreate)
+	if err != nil {
+		return err
+	}
+
+	// set default value for undefined field
+	param.ID = app.NewNullUUID()
+	if !param.IsActive.Valid {
+		param.IsActive = app.NewNullBool(true)
+	}
+	param.CreatedAt = app.NewNullDateTime(time.Now().UTC())
+	param.UpdatedAt = param.CreatedAt
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// save data to db
+	err = tx.Model(param).Create(&param).Error
+	if err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
+// UpdateByID updates the webhook subscription for the specified ID with specified parameters.
+func (u useCase) UpdateByID(id string, param *app.WebhookSubscription, paramUpdate *ParamUpdate) error {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("webhooks.edit")
+	if err != nil {
+		return err
+	}
+
+	// validate param
+	err = u.Ctx.ValidateParam(paramUpdate)
+	if err != nil {
+		return err
+	}
+
+	// get previous data
+	old, err := u.GetByID(id)
+	if err != nil {
+		return err
+	}
+	param.ID = old.ID
+	param.UpdatedAt = app.NewNullDateTime(time.Now().UTC())
+	if param.Secret.String == app.Redacted {
+		param.Secret = app.NullString{} // the masked secret of the previous response is sent back, keep the secret unchanged
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// update data on the db
+	err = tx.Model(param).Where("id = ?", old.ID).Updates(param).Error
+	if err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
+// DeleteByID deletes the webhook subscription for the specified ID.
+func (u useCase) DeleteByID(id string, paramDelete *ParamDelete) error {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("webhooks.delete")
+	if err != nil {
+		return err
+	}
+
+	// get previous data
+	old, err := u.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// update data on the db
+	if err = tx.Model(paramDelete).Where("id = ?", old.ID).Update("deleted_at", time.Now().UTC()).Error; err != nil {
+		return app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
+// GetDeliveries returns the delivery log of the webhook subscription for the specified ID.
+func (u useCase) GetDeliveries(id string) (app.ListModel, error) {
+	res := app.ListModel{}
+
+	// check permission
+	err := u.Ctx.ValidatePermission("webhooks.deliveries")
+	if err != nil {
+		return res, err
+	}
+
+	// prepare db for current ctx
+	tx, err := u.Ctx.DB()
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+
+	// set pagination info
+	u.Query.Set("webhook.id", id)
+	res.Count,
+		res.PageContext.Page,
+		res.PageContext.PerPage,
+		res.PageContext.PageCount,
+		err = app.Query().PaginationInfo(tx, &app.WebhookDelivery{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	// return data count if $per_page set to 0
+	if res.PageContext.PerPage == 0 {
+		return res, err
+	}
+
+	// find data
+	data, err := app.Query().Find(tx, &app.WebhookDelivery{}, u.Query)
+	if err != nil {
+		return res, app.Error().New(http.StatusInternalServerError, err.Error())
+	}
+	res.SetData(data, u.Query)
+	return res, err
+}
+
+// Redeliver sends the payload of the specified delivery of the webhook subscription again.
+func (u useCase) Redeliver(id, deliveryID string) (app.WebhookDelivery, error) {
+
+	// check permission
+	err := u.Ctx.ValidatePermission("webhooks.redeliver")
+	if err != nil {
+		return app.WebhookDelivery{}, err
+	}
+
+	// validate if webhook subscription is exists
+	old, err := u.GetByID(id)
+	if err != nil {
+		return app.WebhookDelivery{}, err
+	}
+	return app.Webhook().Redeliver(*u.Ctx, old.ID.String, deliveryID)
+}
@@ -0,0 +1,31 @@
+package src
+
+import (
+	"grest.dev/cmd/codegentemplate/app"
+	// import : DONT REMOVE THIS COMMENT
+)
+
+func Worker() *workerUtil {
+	if worker == nil {
+		worker = &workerUtil{}
+		worker.Configure()
+		worker.isConfigured = true
+	}
+	return worker
+}
+
+var worker *workerUtil
+
+type workerUtil struct {
+	isConfigured bool
+}
+
+// Configure registers the handlers of the background jobs.
+// The jobs are run by app.Job() workers, enqueue the job with app.Job().Enqueue or useCase.Enqueue.
+func (*workerUtil) Configure() {
+	// register job handler here, for example :
+	// app.RegisterJob("invoices.send_email", invoice.SendEmailJob)
+
+	app.Job()
+	// RegisterJob : DONT REMOVE THIS COMMENT
+}